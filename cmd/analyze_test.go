@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestAnalyze checks that analyze reports a low guess score for a common
+// password and a high one for a random string, without echoing either.
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		password string
+		score    int
+	}{
+		{"password1", 0},
+		{"Xk9#vQ2m!Lp7zR4w", 4},
+	}
+	for _, tt := range tests {
+		rootCmd.SetIn(strings.NewReader(tt.password + "\n"))
+		stdout, _, err := executeRoot(t, "analyze", "--format", "json")
+		rootCmd.SetIn(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(stdout, tt.password) {
+			t.Fatal("output echoes the password")
+		}
+		var got struct {
+			Guesses float64 `json:"guesses"`
+			Score   int     `json:"score"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", stdout, err)
+		}
+		if got.Score != tt.score {
+			t.Errorf("%s: expected score %d, got %d (%g guesses)", tt.password, tt.score, got.Score, got.Guesses)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// batchPassword is a generated password in JSON output.
+type batchPassword struct {
+	Value    string  `json:"value,omitempty"` // Omitted with --no-plaintext
+	Strength string  `json:"strength"`
+	Entropy  float64 `json:"entropy"`
+	Hash     string  `json:"hash,omitempty"` // Set with --hash
+}
+
+// batchResult is the outcome of one options object read by --stdin-options.
+// Exactly one of Passwords and Error is set.
+type batchResult struct {
+	Passwords []batchPassword `json:"passwords,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// runBatch reads a JSON array of password options from r and writes a JSON
+// array with one result per options object to w. Invalid options are reported
+// in their own result and do not stop the rest of the batch.
+func runBatch(r io.Reader, w io.Writer) error {
+	var batch []generator.PasswordOptions
+	if err := json.NewDecoder(r).Decode(&batch); err != nil {
+		return fmt.Errorf("failed to read options from stdin: %w", err)
+	}
+	results := make([]batchResult, len(batch))
+	for i, opts := range batch {
+		passwords, err := generator.GeneratePassword(opts)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Passwords = make([]batchPassword, len(passwords))
+		for j, p := range passwords {
+			results[i].Passwords[j] = batchPassword{Value: p.Value, Strength: p.Strength, Entropy: p.Entropy}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
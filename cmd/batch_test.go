@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestStdinOptions checks that each piped options object produces its own
+// result and that an invalid object does not abort the batch.
+func TestStdinOptions(t *testing.T) {
+	input := `[
+		{"length": 12, "use_numbers": true, "count": 2},
+		{"length": 20, "use_upper": true, "use_lower": true, "count": 1},
+		{"length": 0, "use_lower": true, "count": 1}
+	]`
+	rootCmd.SetIn(strings.NewReader(input))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	stdout, _, err := executeRoot(t, "--stdin-options")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var results []batchResult
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, want := range []struct{ count, length int }{{2, 12}, {1, 20}} {
+		r := results[i]
+		if r.Error != "" || len(r.Passwords) != want.count {
+			t.Fatalf("result %d: expected %d passwords, got %+v", i, want.count, r)
+		}
+		for _, p := range r.Passwords {
+			if len(p.Value) != want.length {
+				t.Errorf("result %d: expected length %d, got %q", i, want.length, p.Value)
+			}
+		}
+	}
+	if results[2].Error == "" || results[2].Passwords != nil {
+		t.Errorf("expected an error for invalid options, got %+v", results[2])
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// charsetsCmd lists the characters in each built-in character class, the
+// optional sets and the pool the character set flags select.
+var charsetsCmd = &cobra.Command{
+	Use:   "charsets",
+	Short: "List the characters in each character class",
+	Long: `List the characters in each built-in character class, the full ASCII
+symbol set, the ambiguous characters, any custom special characters or
+avoided characters, and the effective pool that the given character set flags
+produce, as the root command would draw from.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := charsetOptions(cmd)
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, c := range generator.CharClasses {
+			fmt.Fprintf(w, "%s\t%s\n", c, generator.CharsetFor(c))
+		}
+		fmt.Fprintf(w, "coupon\t%s\n", generator.CouponAlphabet)
+		fmt.Fprintf(w, "full-ascii\t%s\n", generator.FullASCIISymbolSet())
+		fmt.Fprintf(w, "ambiguous\t%s\n", generator.AmbiguousSet(opts))
+		if opts.SpecialChars != "" {
+			fmt.Fprintf(w, "custom-special\t%s\n", opts.SpecialChars)
+		}
+		if opts.AvoidOldChars != "" {
+			fmt.Fprintf(w, "avoided\t%s\n", opts.AvoidOldChars)
+		}
+		fmt.Fprintf(w, "effective\t%s\n", generator.Charset(opts))
+		return w.Flush()
+	},
+}
+
+// charsetFlags names the root command flags that charsets shares to compute
+// the effective pool.
+var charsetFlags = []string{
+	"special", "numbers", "upper", "lower", "special-chars", "full-ascii-symbols",
+	"extra-chars", "exclude-ambiguous", "ambiguous-chars", "avoid-chars",
+	"exclude-homoglyphs", "xml-safe", "identifier-safe", "no-shift", "case-insensitive",
+}
+
+// charsetOptions returns the options selected by the shared character set
+// flags, with the same letter case adjustments as the root command.
+func charsetOptions(cmd *cobra.Command) generator.PasswordOptions {
+	opts := generator.PasswordOptions{
+		UseSpecialChars:   useSpecialChars,
+		UseNumbers:        useNumbers,
+		UseUpper:          useUpper,
+		UseLower:          useLower,
+		SpecialChars:      specialCharSet,
+		FullASCIISymbols:  fullASCIISymbols,
+		ExtraChars:        extraChars,
+		ExcludeAmbiguous:  excludeAmbiguous,
+		AmbiguousChars:    ambiguousChars,
+		AvoidOldChars:     avoidChars,
+		ExcludeHomoglyphs: excludeHomoglyphs,
+		XMLSafe:           xmlSafe,
+		IdentifierSafe:    identifierSafe,
+		NoShift:           noShift,
+		CaseInsensitive:   caseInsensitive,
+	}
+	adjustLetterCase(cmd, &opts)
+	return opts
+}
+
+// init registers the charsets command. Its flags are shared with the root
+// command once those are defined; see shareCharsetFlags.
+func init() {
+	rootCmd.AddCommand(charsetsCmd)
+}
+
+// shareCharsetFlags adds the root command's character set flags to charsets.
+// It is called after the root command defines them.
+func shareCharsetFlags() {
+	for _, name := range charsetFlags {
+		charsetsCmd.Flags().AddFlag(rootCmd.Flags().Lookup(name))
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// parseCharsets returns the characters printed by charsets for each name.
+func parseCharsets(t *testing.T, stdout string) map[string]string {
+	t.Helper()
+	sets := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			t.Fatalf("unexpected line: %q", line)
+		}
+		sets[fields[0]] = fields[1]
+	}
+	return sets
+}
+
+// TestCharsets checks that every character class and optional set is printed
+// with its characters, and that the effective pool follows the flags.
+func TestCharsets(t *testing.T) {
+	stdout, _, err := executeRoot(t, "charsets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sets := parseCharsets(t, stdout)
+	for _, c := range generator.CharClasses {
+		if sets[c.String()] != generator.CharsetFor(c) {
+			t.Errorf("unexpected characters for %s: %q", c, sets[c.String()])
+		}
+	}
+	want := map[string]string{
+		"coupon":     generator.CouponAlphabet,
+		"full-ascii": generator.FullASCIISymbolSet(),
+		"ambiguous":  "0O1lI|",
+		"effective": generator.CharsetFor(generator.ClassUpper) + generator.CharsetFor(generator.ClassLower) +
+			generator.CharsetFor(generator.ClassNumbers) + generator.CharsetFor(generator.ClassSpecial),
+	}
+	for name, chars := range want {
+		if sets[name] != chars {
+			t.Errorf("expected %s %q, got %q", name, chars, sets[name])
+		}
+	}
+	if _, ok := sets["custom-special"]; ok {
+		t.Error("expected no custom special set without --special-chars")
+	}
+
+	stdout, _, err = executeRoot(t, "charsets", "--upper=false", "--special-chars", "#+",
+		"--exclude-ambiguous", "--ambiguous-chars", "0o", "--avoid-chars", "xyz", "--extra-chars", "é")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sets = parseCharsets(t, stdout)
+	want = map[string]string{
+		"custom-special": "#+",
+		"ambiguous":      "0o",
+		"avoided":        "xyz",
+		"effective":      "abcdefghijklmnpqrstuvw123456789#+é",
+	}
+	for name, chars := range want {
+		if sets[name] != chars {
+			t.Errorf("expected %s %q, got %q", name, chars, sets[name])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd reports which of two passwords is stronger.
+var compareCmd = &cobra.Command{
+	Use:   "compare <password1> <password2>",
+	Short: "Report which of two passwords is stronger",
+	Long: `Compare two passwords by their effective entropy, after penalties for
+weaknesses such as repeated blocks, and report which one is stronger.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := generator.CompareStrength(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		switch result {
+		case 1:
+			fmt.Fprintln(out, "Password 1 is stronger")
+		case -1:
+			fmt.Fprintln(out, "Password 2 is stronger")
+		default:
+			fmt.Fprintln(out, "Both passwords are equally strong")
+		}
+		return nil
+	},
+}
+
+// init registers the compare command.
+func init() {
+	rootCmd.AddCommand(compareCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCompare checks that the compare command names the stronger password.
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"x8#Kq2!vLm9@", "abc", "Password 1 is stronger"},
+		{"abc", "x8#Kq2!vLm9@", "Password 2 is stronger"},
+		{"abc", "xyz", "Both passwords are equally strong"},
+	}
+	for _, tt := range tests {
+		stdout, _, err := executeRoot(t, "compare", tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.TrimSpace(stdout); got != tt.want {
+			t.Errorf("compare %q %q: expected %q, got %q", tt.a, tt.b, tt.want, got)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "compare", "abc"); err == nil {
+		t.Error("expected error for a single password")
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// couponCmd generates short, easy-to-read coupon codes.
+var couponCmd = &cobra.Command{
+	Use:   "coupon",
+	Short: "Generate easy-to-read lowercase coupon codes",
+	Long: `Generate shareable coupon codes from the lowercase Crockford base32
+alphabet (0-9 and a-z without i, l, o and u), so codes are easy to read and type.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		codes, err := generator.GenerateFromAlphabet(generator.CouponAlphabet, couponLength, couponCount)
+		if err != nil {
+			return err
+		}
+		for _, code := range codes {
+			fmt.Fprintln(cmd.OutOrStdout(), code)
+		}
+		return nil
+	},
+}
+
+// Coupon flag variables.
+var (
+	couponLength int // Length of each coupon code
+	couponCount  int // Number of coupon codes to generate
+)
+
+// init registers the coupon command and its flags.
+func init() {
+	rootCmd.AddCommand(couponCmd)
+	couponCmd.Flags().IntVarP(&couponLength, "length", "l", 8, "Length of each code")
+	couponCmd.Flags().IntVarP(&couponCount, "count", "c", 1, "Number of codes to generate")
+}
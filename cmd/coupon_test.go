@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// TestCoupon checks that coupon codes only use the coupon alphabet and have the configured length.
+func TestCoupon(t *testing.T) {
+	stdout, _, err := executeRoot(t, "coupon", "--length", "10", "--count", "20")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	codes := strings.Fields(stdout)
+	if len(codes) != 20 {
+		t.Fatalf("expected 20 codes, got %d", len(codes))
+	}
+	for _, code := range codes {
+		if len(code) != 10 {
+			t.Errorf("expected length 10, got %d for %s", len(code), code)
+		}
+		if strings.Trim(code, generator.CouponAlphabet) != "" {
+			t.Errorf("code %s contains characters outside the coupon alphabet", code)
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "coupon")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code := strings.TrimSpace(stdout); len(code) != 8 {
+		t.Errorf("expected a default length of 8, got %q", code)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// entropyCmd prints the entropy of a password read from stdin.
+var entropyCmd = &cobra.Command{
+	Use:   "entropy",
+	Short: "Print the entropy of a password read from stdin",
+	Long: `Read a single line from stdin and print only its entropy in bits, for
+use in scripts. The password itself is never printed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if entropyFormat != formatText && entropyFormat != formatJSON {
+			return fmt.Errorf("invalid format %q (expected %s or %s)", entropyFormat, formatText, formatJSON)
+		}
+		password, err := readPasswordLine(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		entropy, _, err := generator.PasswordEntropy(password)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if entropyFormat == formatJSON {
+			return json.NewEncoder(out).Encode(struct {
+				Entropy float64 `json:"entropy"`
+			}{entropy})
+		}
+		fmt.Fprintf(out, "%.2f\n", entropy)
+		return nil
+	},
+}
+
+// readPasswordLine reads the first line of r without its line ending.
+func readPasswordLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return line, nil
+}
+
+// Entropy flag variables.
+var (
+	entropyFormat string // Output format ("text" or "json")
+)
+
+// init registers the entropy command and its flags.
+func init() {
+	rootCmd.AddCommand(entropyCmd)
+	entropyCmd.Flags().StringVarP(&entropyFormat, "format", "f", formatText, "Output format: text or json")
+}
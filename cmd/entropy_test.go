@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestEntropy checks that the entropy command prints only the number for a piped password.
+func TestEntropy(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader("hunter2\n"))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	stdout, _, err := executeRoot(t, "entropy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, "hunter2") {
+		t.Fatal("output echoes the password")
+	}
+	got, err := strconv.ParseFloat(strings.TrimSpace(stdout), 64)
+	if err != nil {
+		t.Fatalf("expected a single number, got %q", stdout)
+	}
+	// 7 * log2(36) ~= 36.19
+	if want := 7 * math.Log2(36); math.Abs(got-want) > 0.01 {
+		t.Errorf("expected %.2f, got %.2f", want, got)
+	}
+}
+
+// TestEntropy_JSON checks the JSON output and that empty input is rejected.
+func TestEntropy_JSON(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader("abc"))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	stdout, _, err := executeRoot(t, "entropy", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result map[string]float64
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(result) != 1 || math.Abs(result["entropy"]-3*math.Log2(26)) > 1e-9 {
+		t.Errorf("unexpected JSON output: %s", stdout)
+	}
+
+	rootCmd.SetIn(strings.NewReader("\n"))
+	if _, _, err := executeRoot(t, "entropy"); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// Output formats supported by --format.
+const (
+	formatText   = "text"
+	formatDotenv = "dotenv"
+	formatJSON   = "json"
+	formatTable  = "table"
+
+	formatBitwarden = "csv-bitwarden"
+)
+
+// bitwardenHeader is the column layout of Bitwarden's CSV import for
+// individual vaults.
+var bitwardenHeader = []string{
+	"folder", "favorite", "type", "name", "notes", "fields", "reprompt",
+	"login_uri", "login_username", "login_password", "login_totp",
+}
+
+var (
+	// envKeyPattern matches valid environment variable names.
+	envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// dotenvBarePattern matches values that need no quoting in a .env file.
+	dotenvBarePattern = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,-]*$`)
+)
+
+// writeDotenv writes passwords as KEY=value lines. A single password is written
+// as PREFIX=value; several are numbered PREFIX_1, PREFIX_2, and so on. hashes,
+// if not nil, holds the hash of each password, written as KEY_HASH=hash; with
+// --no-plaintext only the hashes are written.
+func writeDotenv(w io.Writer, prefix string, passwords []generator.GeneratedPassword, hashes []string) error {
+	if !envKeyPattern.MatchString(prefix) {
+		return fmt.Errorf("invalid export prefix %q: must be a valid environment variable name", prefix)
+	}
+	for i, p := range passwords {
+		key := prefix
+		if len(passwords) > 1 {
+			key = fmt.Sprintf("%s_%d", prefix, i+1)
+		}
+		if !noPlaintext {
+			fmt.Fprintf(w, "%s=%s\n", key, dotenvQuote(p.Value))
+		}
+		if hashes != nil {
+			fmt.Fprintf(w, "%s_HASH=%s\n", key, dotenvQuote(hashes[i]))
+		}
+	}
+	return nil
+}
+
+// dotenvQuote quotes value following common .env conventions: safe values are
+// left bare, other values are single-quoted (taken literally), and values that
+// contain a single quote are double-quoted with \, ", $ and ` escaped so that
+// sourcing the file in a shell expands nothing.
+func dotenvQuote(value string) string {
+	switch {
+	case dotenvBarePattern.MatchString(value):
+		return value
+	case !strings.Contains(value, "'"):
+		return "'" + value + "'"
+	default:
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+		return `"` + r.Replace(value) + `"`
+	}
+}
+
+// passwordReport is the JSON output of the root command.
+type passwordReport struct {
+	Passwords   []batchPassword        `json:"passwords"`
+	Retries     int                    `json:"retries"`
+	Diagnostics []generator.Diagnostic `json:"diagnostics"`
+}
+
+// writeReport writes passwords as an indented JSON report together with the
+// number of retries and the diagnostics of the run. hashes, if not nil, holds
+// the hash of each password; with --no-plaintext the values are left out.
+func writeReport(w io.Writer, passwords []generator.GeneratedPassword, hashes []string, retries int, diagnostics []generator.Diagnostic) error {
+	report := passwordReport{
+		Passwords:   make([]batchPassword, len(passwords)),
+		Retries:     retries,
+		Diagnostics: diagnostics,
+	}
+	if report.Diagnostics == nil {
+		report.Diagnostics = []generator.Diagnostic{}
+	}
+	for i, p := range passwords {
+		report.Passwords[i] = batchPassword{Value: p.Value, Strength: p.Strength, Entropy: p.Entropy}
+		if noPlaintext {
+			report.Passwords[i].Value = ""
+		}
+		if hashes != nil {
+			report.Passwords[i].Hash = hashes[i]
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode passwords: %w", err)
+	}
+	return nil
+}
+
+// writeTable writes passwords as a table with aligned index, password,
+// strength and entropy columns under a header row. hashes, if not nil, holds
+// the hash of each password, written in a final HASH column. The strength
+// column is colorized like the text output; the colors are applied after
+// alignment so that their escape codes do not count toward the column widths.
+func writeTable(w io.Writer, passwords []generator.GeneratedPassword, hashes []string) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	header := "#\tPASSWORD\tSTRENGTH\tENTROPY"
+	if hashes != nil {
+		header += "\tHASH"
+	}
+	fmt.Fprintln(tw, header)
+	for i, p := range passwords {
+		value := p.Value
+		if noPlaintext {
+			value = "[hidden]"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.2f", i+1, value, p.Strength, p.Entropy)
+		if hashes != nil {
+			fmt.Fprintf(tw, "\t%s", hashes[i])
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// tabwriter pads by rune count, so the strength column starts at the same
+	// rune offset on every line.
+	lines := strings.SplitAfter(buf.String(), "\n")
+	col := utf8.RuneCountInString(lines[0][:strings.Index(lines[0], "STRENGTH")])
+	for i, p := range passwords {
+		runes := []rune(lines[i+1])
+		end := col + utf8.RuneCountInString(p.Strength)
+		lines[i+1] = string(runes[:col]) + colorStrength(p.Strength) + string(runes[end:])
+	}
+	_, err := io.WriteString(w, strings.Join(lines, ""))
+	return err
+}
+
+// writeBitwarden writes passwords as a Bitwarden CSV import file with one
+// login entry per password, named namePrefix followed by its number. The
+// username and URI are left empty to be filled in after the import.
+func writeBitwarden(w io.Writer, namePrefix string, passwords []generator.GeneratedPassword) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(bitwardenHeader); err != nil {
+		return err
+	}
+	for i, p := range passwords {
+		row := make([]string, len(bitwardenHeader))
+		row[2] = "login"
+		row[3] = fmt.Sprintf("%s%d", namePrefix, i+1)
+		row[4] = fmt.Sprintf("Generated by go-passwordgen (%s, %.2f bits)", p.Strength, p.Entropy)
+		row[9] = p.Value
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Histogram layout for --histogram.
+const (
+	histogramBuckets  = 10 // Number of entropy buckets
+	histogramBarWidth = 40 // Width of the longest bar in characters
+)
+
+// writeHistogram writes a text histogram of the effective entropy of passwords,
+// one line per bucket with a bar scaled to the largest bucket.
+func writeHistogram(w io.Writer, passwords []generator.GeneratedPassword) error {
+	buckets, err := generator.EntropyHistogram(passwords, histogramBuckets)
+	if err != nil {
+		return err
+	}
+	largest := 0
+	for _, b := range buckets {
+		largest = max(largest, b.Count)
+	}
+	fmt.Fprintln(w, "Entropy histogram:")
+	for _, b := range buckets {
+		bar := strings.Repeat("#", b.Count*histogramBarWidth/max(largest, 1))
+		fmt.Fprintf(w, "  %7.2f - %7.2f | %-*s %d\n", b.Min, b.Max, histogramBarWidth, bar, b.Count)
+	}
+	return nil
+}
+
+// writeConfig writes the effective generation options as indented JSON.
+// Secret material such as the seed is excluded by the options' JSON tags.
+func writeConfig(w io.Writer, opts generator.PasswordOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(opts); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return nil
+}
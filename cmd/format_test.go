@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// parseDotenv parses KEY=value lines, undoing the quoting applied by dotenvQuote.
+func parseDotenv(t *testing.T, data string) map[string]string {
+	t.Helper()
+	env := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			t.Fatalf("malformed dotenv line: %q", line)
+		}
+		switch {
+		case strings.HasPrefix(value, "'"):
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+		case strings.HasPrefix(value, `"`):
+			value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+			value = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\$`, `$`, "\\`", "`").Replace(value)
+		}
+		env[key] = value
+	}
+	return env
+}
+
+// TestWriteDotenv_Quoting checks that values round-trip through dotenv quoting.
+func TestWriteDotenv_Quoting(t *testing.T) {
+	values := []string{"plainValue123", "a#b c$d", `it's"$x\`, "it's `id`"}
+	passwords := make([]generator.GeneratedPassword, len(values))
+	for i, v := range values {
+		passwords[i].Value = v
+	}
+
+	var buf bytes.Buffer
+	if err := writeDotenv(&buf, "DB", passwords, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "DB_1=plainValue123" {
+		t.Errorf("expected bare value, got %q", lines[0])
+	}
+	if lines[1] != "DB_2='a#b c$d'" {
+		t.Errorf("expected single-quoted value, got %q", lines[1])
+	}
+	if lines[2] != `DB_3="it's\"\$x\\"` {
+		t.Errorf("expected double-quoted escaped value, got %q", lines[2])
+	}
+	if lines[3] != "DB_4=\"it's \\`id\\`\"" {
+		t.Errorf("expected escaped backticks, got %q", lines[3])
+	}
+	env := parseDotenv(t, buf.String())
+	for i, v := range values {
+		if got := env[fmt.Sprintf("DB_%d", i+1)]; got != v {
+			t.Errorf("expected %q to round-trip, got %q", v, got)
+		}
+	}
+
+	if err := writeDotenv(&buf, "1BAD", passwords, nil); err == nil {
+		t.Error("expected error for invalid prefix")
+	}
+}
+
+// TestFormatDotenv checks the keys produced by --format dotenv.
+func TestFormatDotenv(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--format", "dotenv", "--export-prefix", "API_KEY", "--count", "3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env := parseDotenv(t, stdout)
+	for _, key := range []string{"API_KEY_1", "API_KEY_2", "API_KEY_3"} {
+		if v, ok := env[key]; !ok || len(v) != 12 {
+			t.Errorf("expected key %s with a 12-character value, got %q", key, v)
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "--format", "dotenv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := parseDotenv(t, stdout)["PASSWORD"]; !ok {
+		t.Errorf("expected PASSWORD key for a single password, got %q", stdout)
+	}
+
+	stdout, _, err = executeRoot(t, "--format", "dotenv", "--hash", "bcrypt", "--no-plaintext", "--count", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env = parseDotenv(t, stdout)
+	if len(env) != 2 {
+		t.Errorf("expected only the two hashes, got %q", stdout)
+	}
+	for _, key := range []string{"PASSWORD_1_HASH", "PASSWORD_2_HASH"} {
+		if !strings.HasPrefix(env[key], "$2a$") {
+			t.Errorf("expected a bcrypt hash under %s, got %q", key, env[key])
+		}
+	}
+}
+
+// TestHistogram checks that the --histogram buckets sum to the batch size.
+func TestHistogram(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--histogram", "--count", "40", "--length", "6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, histogram, ok := strings.Cut(stdout, "Entropy histogram:\n")
+	if !ok {
+		t.Fatalf("expected a histogram, got:\n%s", stdout)
+	}
+	total := 0
+	for _, line := range strings.Split(histogram, "\n") {
+		if !strings.Contains(line, " | ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		n, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			t.Fatalf("unexpected histogram line %q", line)
+		}
+		total += n
+	}
+	if total != 40 {
+		t.Errorf("expected buckets to sum to 40, got %d:\n%s", total, histogram)
+	}
+}
+
+// TestFormatTable checks that --format table prints a header row and that every
+// column starts at the same position on each line.
+func TestFormatTable(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--format", "table", "--count", "12", "--extra-chars", "äöü")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 13 {
+		t.Fatalf("expected a header and 12 rows, got:\n%s", stdout)
+	}
+	var starts []int
+	for _, name := range []string{"PASSWORD", "STRENGTH", "ENTROPY"} {
+		starts = append(starts, utf8.RuneCountInString(lines[0][:strings.Index(lines[0], name)]))
+	}
+	if !strings.HasPrefix(lines[0], "#") {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+	for i, line := range lines[1:] {
+		runes := []rune(line)
+		if got := strings.Fields(line)[0]; got != strconv.Itoa(i+1) {
+			t.Errorf("expected index %d, got %q", i+1, got)
+		}
+		for _, start := range starts {
+			if len(runes) <= start || runes[start-1] != ' ' || runes[start] == ' ' {
+				t.Errorf("row %q is not aligned with the header at column %d", line, start)
+			}
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "--format", "table", "--count", "2", "--hash", "bcrypt", "--no-plaintext")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines = strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "HASH") {
+		t.Fatalf("expected a HASH column, got:\n%s", stdout)
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if fields[1] != "[hidden]" || !strings.HasPrefix(fields[len(fields)-1], "$2a$") {
+			t.Errorf("expected a hidden password and its bcrypt hash, got %q", line)
+		}
+	}
+}
+
+// TestFormatBitwarden checks that csv-bitwarden output has the header of
+// Bitwarden's importer and one login per password.
+func TestFormatBitwarden(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--format", "csv-bitwarden", "--count", "4", "--name-prefix", "wifi-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp"
+	if got := strings.Join(records[0], ","); got != want {
+		t.Errorf("expected header %q, got %q", want, got)
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 4 rows, got %d", len(records)-1)
+	}
+	for i, row := range records[1:] {
+		if row[2] != "login" || row[3] != "wifi-"+strconv.Itoa(i+1) || len(row[9]) != 12 {
+			t.Errorf("unexpected row %d: %q", i+1, row)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "--format", "csv-bitwarden", "--hash", "bcrypt", "--no-plaintext"); err == nil {
+		t.Error("expected error for csv-bitwarden with --no-plaintext")
+	}
+	if _, _, err := executeRoot(t, "--format", "csv-bitwarden", "--hash", "bcrypt"); err == nil {
+		t.Error("expected error for csv-bitwarden with --hash")
+	}
+}
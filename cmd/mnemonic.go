@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// mnemonicCmd generates a BIP39 mnemonic.
+var mnemonicCmd = &cobra.Command{
+	Use:   "mnemonic",
+	Short: "Generate a BIP39 mnemonic from fresh entropy",
+	Long: `Generate a BIP39 mnemonic of 12, 15, 18, 21 or 24 words from the standard
+English wordlist, including the BIP39 checksum, as used by crypto wallets.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bits, err := generator.MnemonicBits(mnemonicWords)
+		if err != nil {
+			return err
+		}
+		mnemonic, err := generator.GenerateMnemonic(bits)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), mnemonic)
+		return nil
+	},
+}
+
+// Mnemonic flag variables.
+var (
+	mnemonicWords int // Number of words in the mnemonic
+)
+
+// init registers the mnemonic command and its flags.
+func init() {
+	rootCmd.AddCommand(mnemonicCmd)
+	mnemonicCmd.Flags().IntVarP(&mnemonicWords, "words", "w", 24, "Number of words: 12, 15, 18, 21 or 24")
+}
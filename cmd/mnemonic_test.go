@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// TestMnemonic checks the word count and checksum of the mnemonic command output.
+func TestMnemonic(t *testing.T) {
+	stdout, _, err := executeRoot(t, "mnemonic", "--words", "12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mnemonic := strings.TrimSpace(stdout)
+	if n := len(strings.Fields(mnemonic)); n != 12 {
+		t.Errorf("expected 12 words, got %d", n)
+	}
+	if !bip39.IsMnemonicValid(mnemonic) {
+		t.Errorf("mnemonic %q fails BIP39 checksum validation", mnemonic)
+	}
+
+	if _, _, err := executeRoot(t, "mnemonic", "--words", "13"); err == nil {
+		t.Error("expected error for an invalid word count")
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestOnetime checks that onetime prints a base64url token expiring after the
+// requested ttl, and rejects a non-positive ttl or too few or too many bytes.
+func TestOnetime(t *testing.T) {
+	before := time.Now()
+	stdout, _, err := executeRoot(t, "onetime", "--ttl", "1h", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if !regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`).MatchString(got.Token) {
+		t.Errorf("unexpected token %q", got.Token)
+	}
+	if d := got.ExpiresAt.Sub(before); d < time.Hour || d > time.Hour+time.Minute {
+		t.Errorf("expected expiry about an hour from now, got %v", d)
+	}
+
+	if _, _, err := executeRoot(t, "onetime", "--ttl", "0s"); err == nil {
+		t.Error("expected error for a zero ttl")
+	}
+	if _, _, err := executeRoot(t, "onetime", "--bytes", "15"); err == nil {
+		t.Error("expected error for a token shorter than 16 bytes")
+	}
+	if _, _, err := executeRoot(t, "onetime", "--bytes", "0"); err == nil {
+		t.Error("expected error for a zero-byte token")
+	}
+	if _, _, err := executeRoot(t, "onetime", "--bytes", "1025"); err == nil {
+		t.Error("expected error for a token longer than 1024 bytes")
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestComparePolicies checks that every configuration gets a row with its
+// maximum entropy.
+func TestComparePolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	configs := `[
+		{"name": "pin", "length": 6, "use_numbers": true},
+		{"name": "web", "length": 16, "use_lower": true, "use_upper": true, "use_numbers": true}
+	]`
+	if err := os.WriteFile(path, []byte(configs), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stdout, _, err := executeRoot(t, "compare-policies", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", stdout)
+	}
+	want := map[string]float64{"pin": 6 * math.Log2(10), "web": 16 * math.Log2(62)}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			t.Fatalf("unexpected row: %q", line)
+		}
+		if got, entropy := fields[1], fmt.Sprintf("%.2f", want[fields[0]]); got != entropy {
+			t.Errorf("expected %s to have %s bits, got %s", fields[0], entropy, got)
+		}
+		delete(want, fields[0])
+	}
+	if len(want) != 0 {
+		t.Errorf("missing rows for %v", want)
+	}
+	if !strings.Contains(lines[1], "1e+06") {
+		t.Errorf("expected the pin keyspace to be 1e+06, got %q", lines[1])
+	}
+}
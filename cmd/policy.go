@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// exitPolicyViolation is the exit code used when --verify-policy fails.
+const exitPolicyViolation = 2
+
+// policyFromFlags builds the policy described by the --policy-* flags.
+func policyFromFlags() (generator.Policy, error) {
+	p := generator.Policy{
+		Name:       "verify-policy",
+		MinLength:  policyMinLength,
+		MinEntropy: policyMinEntropy,
+	}
+	for _, class := range policyRequire {
+		switch class {
+		case "upper":
+			p.RequireUpper = true
+		case "lower":
+			p.RequireLower = true
+		case "numbers":
+			p.RequireNumbers = true
+		case "special":
+			p.RequireSpecial = true
+		default:
+			return p, fmt.Errorf("unknown character set %q (expected upper, lower, numbers or special)", class)
+		}
+	}
+	return p, nil
+}
+
+// runPolicyCheck validates passwords against the --policy-* thresholds, counting
+// the characters of specials as special as well. Every violation is printed to
+// stderr and an exitError with code 2 is returned if any password fails.
+func runPolicyCheck(cmd *cobra.Command, passwords []generator.GeneratedPassword, specials string) error {
+	policy, err := policyFromFlags()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for i, p := range passwords {
+		violations := policy.CheckWithSpecials(p.Value, specials)
+		for _, v := range violations {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Password %d: %s\n", i+1, v)
+		}
+		if len(violations) > 0 {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	// The flags were valid, so the usage text would only add noise.
+	cmd.SilenceUsage = true
+	return &exitError{
+		code: exitPolicyViolation,
+		err:  fmt.Errorf("%d of %d password(s) violate the policy", failed, len(passwords)),
+	}
+}
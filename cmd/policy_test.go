@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/clipboard"
+	"github.com/efeaslansoyler/go-passwordgen/internal/secretstore"
+)
+
+// TestVerifyPolicy checks that a failing policy exits with code 2 and reports the
+// violations, while a satisfied policy exits with code 0.
+func TestVerifyPolicy(t *testing.T) {
+	_, stderr, err := executeRoot(t, "--verify-policy", "--length", "8", "--special=false",
+		"--policy-min-entropy", "60", "--policy-require", "special")
+	if code := exitCode(err); code != exitPolicyViolation {
+		t.Fatalf("expected exit code %d, got %d (err: %v)", exitPolicyViolation, code, err)
+	}
+	if !strings.Contains(stderr, "must contain a special character") {
+		t.Errorf("expected special character violation in stderr, got %q", stderr)
+	}
+	if !strings.Contains(stderr, "bits of entropy") {
+		t.Errorf("expected entropy violation in stderr, got %q", stderr)
+	}
+
+	stdout, _, err := executeRoot(t, "--verify-policy", "--length", "16",
+		"--policy-min-entropy", "60", "--policy-require", "upper,special", "--quiet")
+	if code := exitCode(err); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (err: %v)", code, err)
+	}
+	if len(strings.TrimSpace(stdout)) != 16 {
+		t.Errorf("expected the password to be printed, got %q", stdout)
+	}
+
+	_, _, err = executeRoot(t, "--verify-policy", "--policy-require", "emoji")
+	if code := exitCode(err); code != 1 {
+		t.Errorf("expected exit code 1 for an invalid flag value, got %d", code)
+	}
+}
+
+// TestVerifyPolicy_NoSideEffects checks that a password failing the policy is
+// never copied, stored or recorded in a manifest.
+func TestVerifyPolicy_NoSideEffects(t *testing.T) {
+	copied := &clipboard.Fake{}
+	origClipboard := newClipboard
+	newClipboard = func() clipboard.Clipboarder { return copied }
+	t.Cleanup(func() { newClipboard = origClipboard })
+	stored := &secretstore.Fake{}
+	origStore := newSecretStore
+	newSecretStore = func() secretstore.Storer { return stored }
+	t.Cleanup(func() { newSecretStore = origStore })
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("auditor key"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "manifest.json")
+	_, _, err := executeRoot(t, "--verify-policy", "--special=false", "--policy-require", "special",
+		"--copy", "--store", "--account", "mail", "--manifest", path, "--manifest-key-file", keyFile)
+	if code := exitCode(err); code != exitPolicyViolation {
+		t.Fatalf("expected exit code %d, got %d (err: %v)", exitPolicyViolation, code, err)
+	}
+	if len(copied.Copied) != 0 || len(stored.Secrets) != 0 {
+		t.Errorf("expected nothing copied or stored, got %v and %v", copied.Copied, stored.Secrets)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no manifest, got %v", err)
+	}
+}
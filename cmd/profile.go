@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// profile is a named preset of generation options selected with --profile.
+type profile struct {
+	description string                               // Shown in the --profile help text
+	apply       func(opt *generator.PasswordOptions) // Adjusts the options for the profile
+	format      func(value string) string            // Optional display formatting of each password
+	words       int                                  // Generate passphrases of this many words instead of passwords
+	plain       bool                                 // Print only the plaintext values, as with --quiet
+}
+
+// profiles lists the presets available to --profile.
+var profiles = map[string]profile{
+	"app-password": {
+		description: "four groups of four lowercase letters, like abcd-efgh-ijkl-mnop",
+		apply: func(opt *generator.PasswordOptions) {
+			opt.Length = 16
+			opt.UseUpper, opt.UseLower, opt.UseNumbers, opt.UseSpecialChars = false, true, false, false
+		},
+		format: func(value string) string {
+			return generator.FormatGrouped(value, 4, "-")
+		},
+	},
+	"pci": {
+		description: "at least 12 characters with upper, lower and digits, checked against PCI DSS 4.0",
+		apply: func(opt *generator.PasswordOptions) {
+			opt.UseUpper, opt.UseLower, opt.UseNumbers = true, true, true
+			opt.Policies = append(opt.Policies, generator.PCIDSS())
+		},
+	},
+	"ssh-passphrase": {
+		description: "words from the BIP39 list for at least 80 bits, printed alone for piping to ssh-keygen",
+		words:       generator.WordsForEntropy(80, 2048),
+		plain:       true,
+	},
+	"wifi": {
+		description: "20 characters from every set, well within the 63-character WPA2 limit",
+		apply: func(opt *generator.PasswordOptions) {
+			opt.Length = 20
+			opt.UseUpper, opt.UseLower, opt.UseNumbers, opt.UseSpecialChars = true, true, true, true
+		},
+	},
+}
+
+// profileNames returns the sorted names of the available profiles.
+func profileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookupProfile returns the named profile or an error listing the valid names.
+func lookupProfile(name string) (profile, error) {
+	p, ok := profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(profileNames(), ", "))
+	}
+	return p, nil
+}
+
+// applyProfile applies the profile to opts. Flags the user set explicitly take
+// precedence over the profile's values.
+func applyProfile(cmd *cobra.Command, p profile, opts *generator.PasswordOptions) {
+	if p.apply != nil {
+		p.apply(opts)
+	}
+	flags := cmd.Flags()
+	if p.words > 0 && !flags.Changed("words") {
+		words = p.words
+	}
+	if p.plain && !flags.Changed("quiet") {
+		quiet = true
+	}
+	if flags.Changed("length") {
+		opts.Length = length
+	}
+	if flags.Changed("special") {
+		opts.UseSpecialChars = useSpecialChars
+	}
+	if flags.Changed("numbers") {
+		opts.UseNumbers = useNumbers
+	}
+	if flags.Changed("upper") {
+		opts.UseUpper = useUpper
+	}
+	if flags.Changed("lower") {
+		opts.UseLower = useLower
+	}
+}
+
+// applyExcludeSets turns off each named character set in opts, after any
+// profile has been applied, so that a set can be dropped from a profile that
+// enables it.
+func applyExcludeSets(opts *generator.PasswordOptions, sets []string) error {
+	for _, set := range sets {
+		switch set {
+		case "upper":
+			opts.UseUpper = false
+		case "lower":
+			opts.UseLower = false
+		case "numbers":
+			opts.UseNumbers = false
+		case "special":
+			opts.UseSpecialChars, opts.FullASCIISymbols = false, false
+		default:
+			return fmt.Errorf("unknown character set %q (expected upper, lower, numbers or special)", set)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// TestProfile_AppPassword checks the exact abcd-efgh-ijkl-mnop shape and that the
+// letters are drawn uniformly.
+func TestProfile_AppPassword(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--profile", "app-password", "--count", "500", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shape := regexp.MustCompile(`^[a-z]{4}-[a-z]{4}-[a-z]{4}-[a-z]{4}$`)
+	counts := make(map[rune]int)
+	lines := strings.Fields(stdout)
+	if len(lines) != 500 {
+		t.Fatalf("expected 500 passwords, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !shape.MatchString(line) {
+			t.Fatalf("password %q does not match the app password shape", line)
+		}
+		for _, r := range strings.ReplaceAll(line, "-", "") {
+			counts[r]++
+		}
+	}
+
+	// Chi-squared goodness of fit against a uniform distribution over 26 letters.
+	// The 0.1% critical value for 25 degrees of freedom is about 52.6.
+	expected := float64(500*16) / 26
+	var chi2 float64
+	for r := 'a'; r <= 'z'; r++ {
+		d := float64(counts[r]) - expected
+		chi2 += d * d / expected
+	}
+	if chi2 > 60 {
+		t.Errorf("letter distribution looks non-uniform (chi-squared = %.1f): %v", chi2, counts)
+	}
+
+	stdout, _, err = executeRoot(t, "--profile", "app-password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Entropy: " + strconv.FormatFloat(16*math.Log2(26), 'f', 2, 64); !strings.Contains(stdout, want) {
+		t.Errorf("expected %q computed on the 16 letters, got %q", want, stdout)
+	}
+}
+
+// TestProfile_Unknown checks that an unknown profile is rejected.
+func TestProfile_Unknown(t *testing.T) {
+	if _, _, err := executeRoot(t, "--profile", "nope"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+// TestProfile_SSHPassphrase checks that ssh-passphrase prints bare passphrases without
+// surrounding whitespace and that the word count meets the 80-bit target.
+func TestProfile_SSHPassphrase(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--profile", "ssh-passphrase", "--count", "20")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 passphrases, got %d:\n%s", len(lines), stdout)
+	}
+	for _, line := range lines {
+		if line != strings.TrimSpace(line) || line == "" {
+			t.Errorf("passphrase %q has whitespace at the edges", line)
+		}
+		if strings.ContainsFunc(line, unicode.IsControl) {
+			t.Errorf("passphrase %q contains control characters", line)
+		}
+		if n := len(strings.Split(line, "-")); float64(n)*11 < 80 {
+			t.Errorf("passphrase %q has %d words, below 80 bits", line, n)
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "--profile", "ssh-passphrase", "--entropy-only")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entropy, err := strconv.ParseFloat(strings.TrimSpace(stdout), 64)
+	if err != nil || entropy < 80 {
+		t.Errorf("expected at least 80 bits, got %q", stdout)
+	}
+}
+
+// TestProfile_PCI checks that the pci profile keeps passwords within PCI DSS even
+// when a shorter length is requested.
+func TestProfile_PCI(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--profile", "pci", "--length", "8", "--count", "20", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Fields(stdout) {
+		if violations := generator.PCIDSS().Check(line); violations != nil {
+			t.Errorf("password %q violates PCI DSS: %v", line, violations)
+		}
+	}
+}
+
+// TestProfile_ExcludeSet checks that --exclude-set drops a set the profile
+// enables while keeping the others.
+func TestProfile_ExcludeSet(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--profile", "wifi", "--exclude-set", "special", "--count", "50", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Fields(stdout)
+	if len(lines) != 50 {
+		t.Fatalf("expected 50 passwords, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if len(line) != 20 {
+			t.Errorf("expected 20 characters, got %q", line)
+		}
+		for _, r := range line {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				t.Fatalf("password %q contains the excluded symbol %q", line, r)
+			}
+		}
+	}
+
+	if _, _, err := executeRoot(t, "--exclude-set", "emoji"); err == nil {
+		t.Error("expected error for an unknown character set")
+	}
+}
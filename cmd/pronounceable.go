@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// pronounceableCmd generates pseudo-words that follow a language's syllable rules.
+var pronounceableCmd = &cobra.Command{
+	Use:   "pronounceable",
+	Short: "Generate pronounceable pseudo-words for a target language",
+	Long: `Generate lowercase pseudo-words built from syllables of a consonant onset, a
+vowel and an optional consonant coda, using only the clusters that look natural
+in the selected language. They are easier to remember than random characters
+but carry less entropy per letter, so choose a longer length.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passwords, err := generator.GeneratePronounceable(generator.PronounceableOptions{
+			Length:   pronounceableLength,
+			Count:    pronounceableCount,
+			Language: pronounceableLanguage,
+		})
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for i, p := range passwords {
+			fmt.Fprintf(out, "Password %d: %s (Strength: %s, Entropy: %.2f)\n",
+				i+1, p.Value, colorStrength(p.Strength), p.Entropy)
+		}
+		return nil
+	},
+}
+
+// Pronounceable flag variables.
+var (
+	pronounceableLength   int    // Number of letters in each word
+	pronounceableCount    int    // Number of words to generate
+	pronounceableLanguage string // Language whose syllable rules are followed
+)
+
+// init registers the pronounceable command and its flags.
+func init() {
+	rootCmd.AddCommand(pronounceableCmd)
+	pronounceableCmd.Flags().IntVarP(&pronounceableLength, "length", "l", 20, "Number of letters in each word")
+	pronounceableCmd.Flags().IntVarP(&pronounceableCount, "count", "c", 1, "Number of words to generate")
+	pronounceableCmd.Flags().StringVar(&pronounceableLanguage, "language", "en", "Language whose syllable rules are followed ("+strings.Join(generator.PronounceableLanguages(), ", ")+")")
+}
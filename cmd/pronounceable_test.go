@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestPronounceable checks the shape of the pronounceable command output and
+// that an unknown language is rejected.
+func TestPronounceable(t *testing.T) {
+	stdout, _, err := executeRoot(t, "pronounceable", "--language", "es", "--length", "10", "--count", "3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shape := regexp.MustCompile(`^Password \d: [a-z]{10} \(Strength: `)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !shape.MatchString(line) {
+			t.Errorf("unexpected line %q", line)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "pronounceable", "--language", "xx"); err == nil {
+		t.Error("expected error for an unknown language")
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+)
+
+// TestRecovery_JSON checks that the JSON output holds one password and the requested number of codes.
+func TestRecovery_JSON(t *testing.T) {
+	stdout, _, err := executeRoot(t, "recovery", "--codes", "6", "--code-length", "8", "--group", "4", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var bundle recoveryBundle
+	if err := json.Unmarshal([]byte(stdout), &bundle); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(bundle.Password) != 16 {
+		t.Errorf("expected a 16-character password, got %q", bundle.Password)
+	}
+	if len(bundle.RecoveryCodes) != 6 {
+		t.Fatalf("expected 6 recovery codes, got %d", len(bundle.RecoveryCodes))
+	}
+	for _, code := range bundle.RecoveryCodes {
+		groups := strings.Split(code, "-")
+		if len(groups) != 2 || len(groups[0]) != 4 || len(groups[1]) != 4 {
+			t.Errorf("expected two groups of four, got %q", code)
+		}
+		if strings.Trim(strings.Join(groups, ""), generator.CouponAlphabet) != "" {
+			t.Errorf("code %s contains characters outside the coupon alphabet", code)
+		}
+	}
+}
+
+// TestRecovery_Text checks that the text output lists one password followed by the codes.
+func TestRecovery_Text(t *testing.T) {
+	stdout, _, err := executeRoot(t, "recovery", "--codes", "3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d:\n%s", len(lines), stdout)
+	}
+	if !strings.HasPrefix(lines[0], "Password: ") || lines[1] != "Recovery codes:" {
+		t.Errorf("unexpected header:\n%s", stdout)
+	}
+
+	if _, _, err := executeRoot(t, "recovery", "--format", "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -24,12 +24,22 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/efeaslansoyler/go-passwordgen/internal/clipboard"
 	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/efeaslansoyler/go-passwordgen/internal/manifest"
+	"github.com/efeaslansoyler/go-passwordgen/internal/secretstore"
+	"github.com/efeaslansoyler/go-passwordgen/internal/statefile"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -42,6 +52,19 @@ var rootCmd = &cobra.Command{
 length and character sets. Supports special characters, numbers, upper and
 lowercase letters.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if stdinOptions {
+			return runBatch(cmd.InOrStdin(), cmd.OutOrStdout())
+		}
+		if countFrom != "" {
+			if cmd.Flags().Changed("count") {
+				return errors.New("--count-from cannot be combined with --count")
+			}
+			n, err := readCountFile(countFrom)
+			if err != nil {
+				return err
+			}
+			count = n
+		}
 		opts := generator.PasswordOptions{
 			Length:          length,
 			UseSpecialChars: useSpecialChars,
@@ -49,23 +72,427 @@ lowercase letters.`,
 			UseUpper:        useUpper,
 			UseLower:        useLower,
 			Count:           count,
+
+			Prefix:                prefix,
+			DateToken:             dateToken,
+			DateTokenAppend:       dateTokenAppend,
+			QAMarker:              qaMarker,
+			SeparatorEvery:        separatorEvery,
+			ExtraChars:            extraChars,
+			AvoidOldChars:         avoidChars,
+			ExcludeAmbiguous:      excludeAmbiguous,
+			AmbiguousChars:        ambiguousChars,
+			AvoidKeyboardAdjacent: avoidAdjacentKeys,
+			NoSequential:          noSequential,
+			NoRepeatedBigrams:     noRepeatedBigrams,
+			MinDistinct:           minDistinct,
+			MinClasses:            minClasses,
+			Workers:               workers,
+			ScatterDigits:         scatterDigits,
+			FullASCIISymbols:      fullASCIISymbols,
+			SpecialChars:          specialCharSet,
+			XMLSafe:               xmlSafe,
+			IdentifierSafe:        identifierSafe,
+			NoShift:               noShift,
+			CaseInsensitive:       caseInsensitive,
+			BalanceCase:           balanceCase,
+			CaseTolerance:         caseTolerance,
+			ExcludeHomoglyphs:     excludeHomoglyphs,
+			RequireCategory:       requireCategory,
+			RequireFromGroups:     requireGroups(requireFrom),
+			MaxUpper:              maxUpper,
+			MaxLower:              maxLower,
+			MaxNumbers:            maxNumbers,
+			MaxSpecial:            maxSpecial,
+			SpecialFrequency:      specialFrequency,
+			UpperRatio:            upperRatio,
+			NoLeadingSpecial:      noStartSpecial,
+			NoTrailingSpecial:     noEndSpecial,
+		}
+		if realSeparator != "" {
+			if utf8.RuneCountInString(realSeparator) != 1 {
+				return errors.New("--real-separator must be a single character")
+			}
+			opts.RealSeparator, _ = utf8.DecodeRuneInString(realSeparator)
+		}
+		if charsetSpec != "" {
+			if err := generator.ApplyCharsetSpec(&opts, charsetSpec); err != nil {
+				return err
+			}
+		}
+		adjustLetterCase(cmd, &opts)
+		var selected profile
+		if profileName != "" {
+			p, err := lookupProfile(profileName)
+			if err != nil {
+				return err
+			}
+			applyProfile(cmd, p, &opts)
+			selected = p
+		}
+		// Every set, with all ASCII symbols, gives the most entropy per character.
+		if maximizeCharset {
+			if targetEntropy <= 0 {
+				return errors.New("--maximize-charset requires --target-entropy")
+			}
+			opts.UseUpper, opts.UseLower, opts.UseNumbers, opts.UseSpecialChars = true, true, true, true
+			opts.FullASCIISymbols = true
+		}
+		if err := applyExcludeSets(&opts, excludeSets); err != nil {
+			return err
+		}
+		if targetEntropy > 0 {
+			if cmd.Flags().Changed("length") {
+				return errors.New("--target-entropy cannot be combined with --length")
+			}
+			n, err := generator.SuggestLength(targetEntropy, opts)
+			if err != nil {
+				return err
+			}
+			opts.Length = n
+		}
+		// JSON consumers get the weak entropy diagnostic instead.
+		if opts.Length < recommendedMinLength && words == 0 && !quiet && !noWarn && outputFormat != formatJSON {
+			fmt.Fprintln(cmd.ErrOrStderr(), color.New(color.FgYellow).Sprintf(
+				"Warning: passwords shorter than %d characters are easy to crack; consider --length %d or more (silence with --no-warn)",
+				recommendedMinLength, recommendedMinLength))
+		}
+		if seedFile != "" {
+			seed, err := os.ReadFile(seedFile)
+			if err != nil {
+				return fmt.Errorf("failed to read seed file: %w", err)
+			}
+			if len(seed) == 0 {
+				return errors.New("seed file is empty")
+			}
+			opts.Seed = seed
+			fmt.Fprintln(cmd.ErrOrStderr(), color.New(color.FgRed, color.Bold).Sprint(
+				"WARNING: --seed-file makes passwords reproducible by anyone with the file. Never use them as real credentials."))
+		}
+		if printConfig {
+			if err := writeConfig(cmd.ErrOrStderr(), opts); err != nil {
+				return err
+			}
+		}
+		passphrase := generator.PassphraseOptions{Words: words, Separator: separator, Count: opts.Count}
+		if len(wordlistSources) > 0 {
+			if passphrase.Words == 0 {
+				return errors.New("--wordlist requires --words")
+			}
+			list, err := loadWordlists(wordlistSources)
+			if err != nil {
+				return err
+			}
+			passphrase.Wordlist = list
+		}
+		if entropyOnly {
+			var entropy float64
+			var err error
+			if passphrase.Words > 0 {
+				entropy, err = generator.PassphraseEntropy(passphrase)
+			} else {
+				entropy, err = generator.MaxEntropy(opts)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%.2f\n", entropy)
+			return nil
+		}
+		switch outputFormat {
+		case formatText, formatDotenv, formatJSON, formatTable, formatBitwarden:
+		default:
+			return fmt.Errorf("unsupported format %q (expected text, dotenv, json, table or csv-bitwarden)", outputFormat)
+		}
+		if noPlaintext && outputFormat == formatBitwarden {
+			return errors.New("--format csv-bitwarden imports the plaintext and cannot be combined with --no-plaintext")
+		}
+		if hashAlgo != "" && outputFormat == formatBitwarden {
+			return errors.New("--format csv-bitwarden has no column for hashes and cannot be combined with --hash")
+		}
+		if noPlaintext && hashAlgo == "" {
+			return errors.New("--no-plaintext requires --hash")
+		}
+		var manifestKey []byte
+		if manifestPath != "" {
+			if manifestKeyFile == "" {
+				return errors.New("--manifest requires --manifest-key-file")
+			}
+			var err error
+			if manifestKey, err = os.ReadFile(manifestKeyFile); err != nil {
+				return fmt.Errorf("failed to read manifest key file: %w", err)
+			}
+			if len(manifestKey) == 0 {
+				return errors.New("manifest key file is empty")
+			}
+		} else if manifestKeyFile != "" {
+			return errors.New("--manifest-key-file requires --manifest")
+		}
+		if storeSecret && account == "" {
+			return errors.New("--store requires --account")
+		}
+		if storeSecret && count > 1 && !concat {
+			return errors.New("--store saves a single password; use --count 1 or --concat")
+		}
+		if noPlaintext && phonetic {
+			return errors.New("--phonetic spells out the password and cannot be combined with --no-plaintext")
+		}
+		if numbered && !quiet {
+			return errors.New("--numbered requires --quiet")
+		}
+		if numbered && outputFormat != formatText {
+			return fmt.Errorf("--numbered only applies to text output and cannot be combined with --format %s", outputFormat)
+		}
+		if noPlaintext && reverse {
+			return errors.New("--reverse reveals the password and cannot be combined with --no-plaintext")
+		}
+		var algo generator.HashAlgo
+		if hashAlgo != "" {
+			var err error
+			if algo, err = generator.ParseHashAlgo(hashAlgo); err != nil {
+				return err
+			}
+		}
+		if filterStrength != "" {
+			if _, _, err := generator.FilterByStrength(nil, filterStrength); err != nil {
+				return err
+			}
+		}
+		if outputFormat == formatDotenv && !envKeyPattern.MatchString(exportPrefix) {
+			return fmt.Errorf("invalid export prefix %q: must be a valid environment variable name", exportPrefix)
+		}
+		if uniqueState != "" && passphrase.Words > 0 {
+			return errors.New("--unique-across-runs is not supported with --words")
+		}
+		// Every check above runs for --dry-run too, so it accepts exactly what a
+		// real run accepts.
+		if dryRun {
+			var err error
+			if passphrase.Words > 0 {
+				_, err = generator.PassphraseEntropy(passphrase)
+			} else {
+				err = generator.ValidateOptions(opts)
+			}
+			if err != nil {
+				// The flags parsed, so the usage text would only add noise.
+				cmd.SilenceUsage = true
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "valid")
+			return nil
+		}
+		if progress {
+			opts.OnProgress, opts.ProgressEvery = progressReporter(cmd.ErrOrStderr(), count)
 		}
 		start := time.Now()
-		passwords, err := generator.GeneratePassword(opts)
+		var store *statefile.Store
+		if uniqueState != "" {
+			var err error
+			if store, err = statefile.Open(uniqueState); err != nil {
+				return err
+			}
+			opts.Reject = func(value string) bool {
+				if store.Contains(value) {
+					return true
+				}
+				store.Add(value)
+				return false
+			}
+		}
+		var result generator.GenerateResult
+		var err error
+		if passphrase.Words > 0 {
+			result.Passwords, err = generator.GeneratePassphrase(passphrase)
+		} else {
+			result, err = generator.GenerateWithResult(opts)
+		}
+		if store != nil {
+			// Record the passwords before printing them, and only if generation succeeded.
+			if err != nil {
+				store.Discard()
+			} else {
+				err = store.Close()
+			}
+		}
 		if err != nil {
 			return err
 		}
-		elapsed := time.Since(start)
+		finished := time.Now()
+		elapsed := finished.Sub(start)
+		passwords := result.Passwords
+		if concat {
+			passwords = []generator.GeneratedPassword{generator.Concatenate(passwords)}
+		}
+		if checkChar {
+			generator.AddCheckChars(passwords, nil)
+		}
+		if filterStrength != "" {
+			var filtered int
+			passwords, filtered, err = generator.FilterByStrength(passwords, filterStrength)
+			if err != nil {
+				return err
+			}
+			if verbose {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Filtered %d password(s) below %s\n", filtered, filterStrength)
+			}
+			if storeSecret && len(passwords) == 0 {
+				return fmt.Errorf("no password reached --filter-strength %s, nothing to store", filterStrength)
+			}
+		}
+		diagnostics := result.Diagnostics
+		if distinctCheck {
+			if distinct := generator.CountDistinct(passwords); distinct < len(passwords) {
+				diagnostics = append(diagnostics, generator.Diagnostic{
+					Code:    generator.DiagnosticDuplicates,
+					Message: fmt.Sprintf("only %d of %d passwords are distinct; the keyspace is too small for this batch", distinct, len(passwords)),
+				})
+			}
+		}
+		if outputFormat != formatJSON && !noWarn {
+			for _, d := range diagnostics {
+				// Short lengths are already warned about above, so the weak
+				// entropy warning is only an extra detail on stderr.
+				if d.Code == generator.DiagnosticWeakEntropy && !verbose {
+					continue
+				}
+				fmt.Fprintln(cmd.ErrOrStderr(), color.New(color.FgYellow).Sprint("Warning: "+d.Message))
+			}
+		}
+
+		if selected.format != nil {
+			for i := range passwords {
+				passwords[i].Value = selected.format(passwords[i].Value)
+			}
+		}
+
+		// Check the policy before anything is recorded, copied or stored.
+		if verifyPolicy {
+			if err := runPolicyCheck(cmd, passwords, generator.SpecialSet(opts)); err != nil {
+				return err
+			}
+		}
+
+		if manifestPath != "" {
+			var options any = opts
+			if passphrase.Words > 0 {
+				options = passphrase
+			}
+			if err := writeManifest(manifestPath, manifestKey, options, start, finished, passwords); err != nil {
+				return err
+			}
+		}
+
+		var hashes []string
+		if hashAlgo != "" {
+			hashes, err = hashPasswords(passwords, algo)
+			if err != nil {
+				return err
+			}
+		}
+
+		if copyToClipboard {
+			values := make([]string, len(passwords))
+			for i, p := range passwords {
+				values[i] = p.Value
+			}
+			if err := newClipboard().Copy(strings.Join(values, "\n")); err != nil {
+				return fmt.Errorf("failed to copy to clipboard: %w", err)
+			}
+		}
+
+		if storeSecret {
+			if err := newSecretStore().Store(account, passwords[0].Value); err != nil {
+				return fmt.Errorf("failed to store password: %w", err)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		switch outputFormat {
+		case formatDotenv:
+			return writeDotenv(out, exportPrefix, passwords, hashes)
+		case formatJSON:
+			return writeReport(out, passwords, hashes, result.Retries, diagnostics)
+		case formatTable:
+			return writeTable(out, passwords, hashes)
+		case formatBitwarden:
+			return writeBitwarden(out, namePrefix, passwords)
+		}
+		var stamp string
+		if timestamp {
+			stamp = finished.Format(time.RFC3339)
+		}
 		if quiet {
-			for _, p := range passwords {
-				fmt.Println(p.Value)
+			for i, p := range passwords {
+				var fields []string
+				if numbered {
+					fields = append(fields, strconv.Itoa(i+1))
+				}
+				if timestamp {
+					fields = append(fields, stamp)
+				}
+				if !noPlaintext {
+					fields = append(fields, p.Value)
+				}
+				if hashes != nil {
+					fields = append(fields, hashes[i])
+				}
+				if fingerprint {
+					fields = append(fields, generator.Fingerprint(p.Value))
+				}
+				if phonetic {
+					fields = append(fields, generator.Phonetic(p.Value))
+				}
+				if reverse {
+					fields = append(fields, generator.ReverseRunes(p.Value))
+				}
+				fmt.Fprintln(out, strings.Join(fields, "\t"))
 			}
 		} else {
 			for i, p := range passwords {
-				fmt.Printf("Password %d: %s (Strength: %s, Entropy: %.2f)\n",
-					i+1, p.Value, colorStrength(p.Strength), p.Entropy)
+				value := p.Value
+				if noPlaintext {
+					value = "[hidden]"
+				}
+				if timestamp {
+					fmt.Fprint(out, stamp+" ")
+				}
+				fmt.Fprintf(out, "Password %d: %s (Strength: %s, Entropy: %.2f)\n",
+					i+1, value, colorStrength(p.Strength), p.Entropy)
+				if hashes != nil {
+					fmt.Fprintf(out, "  Hash: %s\n", hashes[i])
+				}
+				if fingerprint {
+					fmt.Fprintf(out, "  Fingerprint: %s\n", generator.Fingerprint(p.Value))
+				}
+				if phonetic {
+					fmt.Fprintf(out, "  Phonetic: %s\n", generator.Phonetic(p.Value))
+				}
+				if reverse {
+					fmt.Fprintf(out, "  Reversed: %s\n", generator.ReverseRunes(p.Value))
+				}
+			}
+			if specialFrequency > 0 {
+				fmt.Fprintln(out, "Note: --special-frequency biases the character distribution; entropy assumes uniform draws")
+			}
+			if upperRatio > 0 {
+				fmt.Fprintln(out, "Note: --upper-ratio biases the character distribution; entropy assumes uniform draws")
+			}
+			if showRetries {
+				fmt.Fprintf(out, "Retries: %d\n", result.Retries)
+			}
+			if histogram {
+				if err := writeHistogram(out, passwords); err != nil {
+					return err
+				}
 			}
-			fmt.Printf("Generation time: %s\n", elapsed)
+			if copyToClipboard {
+				fmt.Fprintf(out, "Copied %d password(s) to the clipboard\n", len(passwords))
+			}
+			if storeSecret {
+				fmt.Fprintf(out, "Stored the password in the secret store under account %q\n", account)
+			}
+			stats := generator.Stats{Count: len(result.Passwords), Elapsed: elapsed}
+			fmt.Fprintf(out, "Generation time: %s (%.0f passwords/s)\n", elapsed, stats.Throughput())
 		}
 		return nil
 	},
@@ -76,21 +503,235 @@ lowercase letters.`,
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitError is an error that requests a specific process exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error implements the error interface.
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the process exit code for an error returned by a command:
+// 0 for nil, the requested code for an exitError, and 1 otherwise.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
 	}
+	return 1
 }
 
 // CLI flag variables.
 var (
-	length          int  // Length of the generated password(s)
-	useSpecialChars bool // Include special characters in the password
-	useNumbers      bool // Include numbers in the password
-	useUpper        bool // Include uppercase letters in the password
-	useLower        bool // Include lowercase letters in the password
-	count           int  // Number of passwords to generate
-	quiet           bool // Print only the password(s), suppress extra output
+	length          int    // Length of the generated password(s)
+	useSpecialChars bool   // Include special characters in the password
+	useNumbers      bool   // Include numbers in the password
+	useUpper        bool   // Include uppercase letters in the password
+	useLower        bool   // Include lowercase letters in the password
+	count           int    // Number of passwords to generate
+	countFrom       string // File containing the number of passwords to generate
+	quiet           bool   // Print only the password(s), suppress extra output
+	progress        bool   // Report generation progress on stderr
+	hashAlgo        string // Hash algorithm applied to each password ("bcrypt" or "argon2id")
+	noPlaintext     bool   // Hide the plaintext password when hashing
+	profileName     string // Named preset of options
+
+	specialFrequency  float64 // Probability that a fill position is a special character
+	upperRatio        float64 // Probability that a fill letter is uppercase
+	maxUpper          int     // Maximum number of uppercase letters (0 = unlimited)
+	maxLower          int     // Maximum number of lowercase letters (0 = unlimited)
+	maxNumbers        int     // Maximum number of digits (0 = unlimited)
+	maxSpecial        int     // Maximum number of special characters (0 = unlimited)
+	noStartSpecial    bool    // Never start a password with a special character
+	noEndSpecial      bool    // Never end a password with a special character
+	checkChar         bool    // Append a check character to each password
+	copyToClipboard   bool    // Copy the generated password(s) to the clipboard
+	storeSecret       bool    // Save the generated password in the OS secret store
+	account           string  // Account name the password is stored under
+	entropyOnly       bool    // Print the entropy of the configuration without generating
+	dryRun            bool    // Validate the options without generating
+	outputFormat      string  // Output format ("text", "dotenv" or "json")
+	exportPrefix      string  // Variable name prefix for dotenv output
+	namePrefix        string  // Entry name prefix for password manager imports
+	prefix            string  // Non-secret tag prepended to each password
+	seedFile          string  // File whose contents seed deterministic generation
+	manifestPath      string  // File receiving a signed manifest of the run
+	manifestKeyFile   string  // File holding the manifest signing key
+	extraChars        string  // Extra characters added to the pool
+	stdinOptions      bool    // Read a JSON array of options from stdin and print JSON results
+	avoidChars        string  // Characters left out of every set, e.g. those of an old password
+	excludeAmbiguous  bool    // Leave out easily confused characters
+	ambiguousChars    string  // Characters treated as ambiguous instead of the built-in list
+	excludeHomoglyphs bool    // Drop extra characters that look like pool characters
+	avoidAdjacentKeys bool    // Reject consecutive characters on neighbouring QWERTY keys
+	noSequential      bool    // Reject runs such as abc or 321
+	noRepeatedBigrams bool    // Reject passwords repeating a two-character sequence
+	minDistinct       int     // Minimum number of distinct characters
+	minClasses        int     // Minimum number of character sets in each password
+	workers           int     // Goroutines generating passwords (0 = auto)
+	printConfig       bool    // Print the effective options as JSON to stderr
+	balanceCase       bool    // Keep uppercase and lowercase counts close
+	scatterDigits     bool    // Reject passwords ending in a run of digits
+	dateToken         string  // Date token format added to each password
+	dateTokenAppend   bool    // Append the date token instead of prepending it
+	showRetries       bool    // Report how many candidates were regenerated
+	fullASCIISymbols  bool    // Use all printable ASCII punctuation as special characters
+	specialCharSet    string  // Custom special characters replacing the built-in set
+	xmlSafe           bool    // Leave out characters XML and HTML escape
+	identifierSafe    bool    // Generate valid Go and shell identifiers
+	noShift           bool    // Use only characters typed without Shift
+	charsetSpec       string  // POSIX classes selecting the character sets
+	words             int     // Generate passphrases of this many words instead of passwords
+	separator         string  // Separator between passphrase words
+	uniqueState       string  // State file of previously issued passwords
+	fingerprint       bool    // Print a short SHA-256 fingerprint of each password
+	caseInsensitive   bool    // Use a single letter case for case-folding systems
+	distinctCheck     bool    // Warn when the batch contains duplicate passwords
+	phonetic          bool    // Print the NATO phonetic spelling of each password
+	reverse           bool    // Print each password reversed as well
+	concat            bool    // Join the generated passwords into a single secret
+	timestamp         bool    // Prefix each password line with the RFC 3339 generation time
+	histogram         bool    // Print a histogram of the batch's entropy values
+	noWarn            bool    // Suppress warnings on stderr
+	numbered          bool    // Prefix each quiet output line with its 1-based index
+	verbose           bool    // Report extra details on stderr
+	filterStrength    string  // Leave out passwords below this strength label
+	caseTolerance     int     // Maximum upper/lower count difference with --balance-case
+	targetEntropy     float64 // Choose the shortest length reaching this many bits
+	maximizeCharset   bool    // Enable every set to minimize the length for --target-entropy
+
+	requireCategory []string // Unicode categories that must each appear at least once
+	excludeSets     []string // Character sets turned off after applying the profile
+	requireFrom     []string // Groups of characters that must each appear at least once
+	wordlistSources []string // Word list files or bip39:<language> lists for passphrases
+	qaMarker        string   // Marker embedded in test-data passwords
+	realSeparator   string   // Separator stored as part of each password
+	separatorEvery  int      // Random characters between real separators
+
+	verifyPolicy     bool     // Fail with exit code 2 if a password violates the policy
+	policyMinLength  int      // Policy: minimum password length
+	policyMinEntropy float64  // Policy: minimum entropy in bits
+	policyRequire    []string // Policy: required character sets
 )
 
+// newClipboard returns the clipboard backend used by --copy.
+// Tests replace it with a fake.
+var newClipboard = clipboard.New
+
+// newSecretStore returns the secret store backend used by --store.
+// Tests replace it with a fake.
+var newSecretStore = secretstore.New
+
+// maxCountFromFile is the largest count --count-from accepts.
+const maxCountFromFile = 1_000_000
+
+// readCountFile reads the password count for --count-from from path, which
+// must contain a single integer between 1 and maxCountFromFile, optionally
+// surrounded by whitespace.
+func readCountFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read count file: %w", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("count file must contain a single integer: %w", err)
+	}
+	if n < 1 || n > maxCountFromFile {
+		return 0, fmt.Errorf("count file value %d is out of range (1-%d)", n, maxCountFromFile)
+	}
+	return n, nil
+}
+
+// writeManifest signs a manifest of the run with key and writes it to path. It
+// records the keyed fingerprints of passwords, never their values.
+func writeManifest(path string, key []byte, options any, started, finished time.Time, passwords []generator.GeneratedPassword) error {
+	values := make([]string, len(passwords))
+	for i, p := range passwords {
+		values[i] = p.Value
+	}
+	m, err := manifest.New(key, options, started, finished, values)
+	if err != nil {
+		return err
+	}
+	if err := m.Sign(key); err != nil {
+		return err
+	}
+	return manifest.Write(path, m)
+}
+
+// adjustLetterCase drops the letter cases that --no-shift and
+// --case-insensitive leave out unless they were requested explicitly.
+func adjustLetterCase(cmd *cobra.Command, opts *generator.PasswordOptions) {
+	// Without Shift, uppercase letters are dropped unless requested explicitly,
+	// which validation then rejects.
+	if noShift && !cmd.Flags().Changed("upper") {
+		opts.UseUpper = false
+	}
+	// Case-insensitive passwords keep the letter case set explicitly, lowercase by default.
+	if caseInsensitive && opts.UseUpper && opts.UseLower {
+		if !cmd.Flags().Changed("upper") {
+			opts.UseUpper = false
+		} else if !cmd.Flags().Changed("lower") {
+			opts.UseLower = false
+		}
+	}
+}
+
+// requireGroups converts the --require-from groups to rune groups.
+func requireGroups(groups []string) [][]rune {
+	var runes [][]rune
+	for _, g := range groups {
+		runes = append(runes, []rune(g))
+	}
+	return runes
+}
+
+// loadWordlists returns the union of the word lists named by sources: bundled
+// BIP39 lists as bip39:<language>, otherwise files with one word per line.
+func loadWordlists(sources []string) ([]string, error) {
+	lists := make([][]string, 0, len(sources))
+	for _, source := range sources {
+		if language, ok := strings.CutPrefix(source, "bip39:"); ok {
+			list, err := generator.BIP39Wordlist(language)
+			if err != nil {
+				return nil, err
+			}
+			lists = append(lists, list)
+			continue
+		}
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open wordlist: %w", err)
+		}
+		list, err := generator.LoadWordlist(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return generator.MergeWordlists(lists...), nil
+}
+
+// recommendedMinLength is the shortest --length that does not trigger a warning.
+const recommendedMinLength = 12
+
 // Version holds the application version, set at build time via -ldflags.
 var Version = "dev"
 
@@ -102,8 +743,89 @@ func init() {
 	rootCmd.Flags().BoolVarP(&useNumbers, "numbers", "n", true, "Use numbers")
 	rootCmd.Flags().BoolVarP(&useUpper, "upper", "u", true, "Use uppercase letters")
 	rootCmd.Flags().BoolVarP(&useLower, "lower", "o", true, "Use lowercase letters")
+	rootCmd.Flags().Float64Var(&targetEntropy, "target-entropy", 0, "Use the shortest length reaching this many bits of entropy with the selected sets (replaces --length)")
+	rootCmd.Flags().BoolVar(&maximizeCharset, "maximize-charset", false, "Enable every set, including all ASCII symbols, for the shortest length reaching --target-entropy")
 	rootCmd.Flags().IntVarP(&count, "count", "c", 1, "Number of passwords to generate")
+	rootCmd.Flags().StringVar(&countFrom, "count-from", "", fmt.Sprintf("Read the number of passwords from a file containing a single integer (1-%d)", maxCountFromFile))
+	rootCmd.Flags().BoolVar(&concat, "concat", false, "Join the --count generated passwords into one secret with their entropy combined")
+	rootCmd.Flags().IntVar(&workers, "workers", 0, "Goroutines generating passwords concurrently (0 = auto from the count and CPUs)")
+	rootCmd.Flags().IntVarP(&words, "words", "w", 0, "Generate passphrases of this many words instead of passwords (0 = password mode)")
+	rootCmd.Flags().StringSliceVar(&wordlistSources, "wordlist", nil, "Draw passphrase words from these lists merged, e.g. bip39:english and a file with one word per line")
+	rootCmd.Flags().StringVar(&separator, "separator", "-", "Separator between passphrase words")
 	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Suppress output (print only password(s))")
+	rootCmd.Flags().BoolVar(&numbered, "numbered", false, "With --quiet and text output, prefix each line with its 1-based index and a tab")
+	rootCmd.Flags().BoolVar(&noWarn, "no-warn", false, "Suppress warnings on stderr, such as for short lengths or weak entropy")
+	rootCmd.Flags().StringVarP(&profileName, "profile", "p", "", "Use a preset of options ("+strings.Join(profileNames(), ", ")+")")
+	rootCmd.Flags().StringSliceVar(&excludeSets, "exclude-set", nil, "Turn off these character sets (upper, lower, numbers, special) even if the profile enables them")
+	rootCmd.Flags().BoolVar(&progress, "progress", false, "Show generation progress on stderr (terminals only)")
+	rootCmd.Flags().StringVar(&hashAlgo, "hash", "", "Also print a hash of each password (bcrypt or argon2id in PHC format, salt included)")
+	rootCmd.Flags().BoolVar(&caseInsensitive, "case-insensitive", false, "Use a single letter case for systems that ignore case (lowercase unless --upper is set)")
+	rootCmd.Flags().StringVar(&charsetSpec, "charset-spec", "", "Select the character sets with POSIX classes, e.g. '[:alnum:][:punct:]' (replaces -s, -n, -u and -o)")
+	rootCmd.Flags().BoolVar(&noShift, "no-shift", false, "Use only characters typed without Shift: lowercase, digits and -=[];',./")
+	rootCmd.Flags().BoolVar(&xmlSafe, "xml-safe", false, "Leave out characters XML and HTML escape (< > & \" ') so passwords embed verbatim")
+	rootCmd.Flags().BoolVar(&identifierSafe, "identifier-safe", false, "Generate valid Go and shell identifiers: [A-Za-z0-9_], never starting with a digit")
+	rootCmd.Flags().BoolVar(&fullASCIISymbols, "full-ascii-symbols", false, "Use all printable ASCII punctuation as special characters, including quotes and backslash")
+	rootCmd.Flags().StringVar(&specialCharSet, "special-chars", "", "Replace the special characters with this set of ASCII punctuation, which may include a space (never placed at an edge)")
+	rootCmd.Flags().Float64Var(&specialFrequency, "special-frequency", 0, "Probability (0-1) that a fill character is special (0 = uniform)")
+	rootCmd.Flags().Float64Var(&upperRatio, "upper-ratio", 0, "Probability (0-1) that a fill letter is uppercase, for mostly-uppercase or mostly-lowercase looks (0 = uniform)")
+	rootCmd.Flags().StringVar(&uniqueState, "unique-across-runs", "", "Never repeat a password recorded in this state file, and record the new ones (salted hashes only)")
+	rootCmd.Flags().StringVar(&manifestPath, "manifest", "", "Write a signed JSON manifest of the options, timestamps and password fingerprints to this file")
+	rootCmd.Flags().StringVar(&manifestKeyFile, "manifest-key-file", "", "File holding the HMAC key that signs the --manifest")
+	rootCmd.Flags().StringVar(&seedFile, "seed-file", "", "Seed deterministic generation from a file (INSECURE: for reproducible test fixtures only)")
+	rootCmd.Flags().StringVar(&extraChars, "extra-chars", "", "Extra characters to add to the pool, e.g. Cyrillic letters or emoji")
+	rootCmd.Flags().BoolVar(&stdinOptions, "stdin-options", false, "Read a JSON array of option objects from stdin and print the generated passwords for each as JSON")
+	rootCmd.Flags().BoolVar(&excludeAmbiguous, "exclude-ambiguous", false, "Leave out easily confused characters (0 O 1 l I | unless --ambiguous-chars is set)")
+	rootCmd.Flags().StringVar(&ambiguousChars, "ambiguous-chars", "", "Characters --exclude-ambiguous treats as ambiguous, to match your display font")
+	rootCmd.Flags().StringVar(&avoidChars, "avoid-chars", "", "Leave these characters out of every set, e.g. the characters of the password being replaced")
+	rootCmd.Flags().StringVar(&realSeparator, "real-separator", "", "Insert this character into the password itself every --separator-every characters (counted by the target system, unlike display grouping)")
+	rootCmd.Flags().IntVar(&separatorEvery, "separator-every", 0, "Random characters between real separators (requires --real-separator)")
+	rootCmd.Flags().StringVar(&qaMarker, "qa-marker", "", "Embed this marker (Qa if given without a value) at a random position to flag passwords as QA test data")
+	rootCmd.Flags().Lookup("qa-marker").NoOptDefVal = "Qa"
+	rootCmd.Flags().StringArrayVar(&requireFrom, "require-from", nil, "Require a character from this group, e.g. '!@#' (repeatable, one group per flag)")
+	rootCmd.Flags().StringSliceVar(&requireCategory, "require-category", nil, "Require a rune from each Unicode category (e.g. Lu, So), drawn from --extra-chars")
+	rootCmd.Flags().BoolVar(&excludeHomoglyphs, "exclude-homoglyphs", false, "Drop extra characters that look like characters already in the pool")
+	rootCmd.Flags().BoolVar(&avoidAdjacentKeys, "avoid-adjacent-keys", false, "Avoid consecutive characters on neighbouring US QWERTY keys")
+	rootCmd.Flags().BoolVar(&noSequential, "no-sequential", false, "Avoid ascending or descending runs of 3+ letters or digits, such as abc or 321")
+	rootCmd.Flags().BoolVar(&noRepeatedBigrams, "no-repeated-bigrams", false, "Avoid repeating any two-character sequence, such as ab in xabyab")
+	rootCmd.Flags().IntVar(&minDistinct, "min-distinct", 0, "Minimum number of distinct characters (0 = no minimum)")
+	rootCmd.Flags().IntVar(&minClasses, "min-classes", 0, "Require only this many of the selected sets per password, chosen at random (0 = all of them)")
+	rootCmd.Flags().BoolVar(&distinctCheck, "count-distinct-check", false, "Warn on stderr if the batch contains duplicate passwords")
+	rootCmd.Flags().BoolVar(&showRetries, "retries", false, "Report how many candidates were regenerated to satisfy the constraints")
+	rootCmd.Flags().BoolVar(&printConfig, "print-config", false, "Print the effective options as JSON to stderr before generating (seeds are omitted)")
+	rootCmd.Flags().BoolVar(&balanceCase, "balance-case", false, "Keep the number of uppercase and lowercase letters roughly equal")
+	rootCmd.Flags().IntVar(&caseTolerance, "case-tolerance", 1, "Maximum difference between uppercase and lowercase counts with --balance-case")
+	rootCmd.Flags().BoolVar(&scatterDigits, "scatter-digits", false, "Do not end passwords with a run of digits")
+	rootCmd.Flags().StringVar(&prefix, "prefix", "", "Non-secret tag prepended to each password (letters, digits, '-' and '_')")
+	rootCmd.Flags().StringVar(&dateToken, "date-token", "", "Date token added to each password, e.g. '%YQ%q-' (%Y %y %m %d %q %V %%; reduces secrecy)")
+	rootCmd.Flags().BoolVar(&dateTokenAppend, "date-token-append", false, "Append the date token instead of prepending it")
+	rootCmd.Flags().IntVar(&maxUpper, "max-upper", 0, "Maximum number of uppercase letters (0 = unlimited)")
+	rootCmd.Flags().IntVar(&maxLower, "max-lower", 0, "Maximum number of lowercase letters (0 = unlimited)")
+	rootCmd.Flags().IntVar(&maxNumbers, "max-numbers", 0, "Maximum number of digits (0 = unlimited)")
+	rootCmd.Flags().IntVar(&maxSpecial, "max-special", 0, "Maximum number of special characters (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&noStartSpecial, "no-start-special", false, "Do not start passwords with a special character")
+	rootCmd.Flags().BoolVar(&noEndSpecial, "no-end-special", false, "Do not end passwords with a special character")
+	rootCmd.Flags().BoolVar(&checkChar, "check-char", false, "Append a mod-36 check character (0-9A-Z) to each password")
+	rootCmd.Flags().BoolVar(&copyToClipboard, "copy", false, "Copy the generated password(s) to the clipboard")
+	rootCmd.Flags().BoolVar(&storeSecret, "store", false, "Save the password in the OS secret store (Keychain or Secret Service) instead of exposing it on the clipboard")
+	rootCmd.Flags().StringVar(&account, "account", "", "Account name to store the password under with --store")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate the options and print \"valid\" or the error, without generating a password")
+	rootCmd.Flags().BoolVar(&entropyOnly, "entropy-only", false, "Print only the entropy of the configuration, without generating a password")
+	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", formatText, "Output format (text, dotenv, json, table or csv-bitwarden)")
+	rootCmd.Flags().StringVar(&namePrefix, "name-prefix", "password-", "Entry name prefix for csv-bitwarden output, followed by the password number")
+	rootCmd.Flags().StringVar(&exportPrefix, "export-prefix", "PASSWORD", "Variable name prefix for dotenv output")
+	rootCmd.Flags().BoolVar(&verifyPolicy, "verify-policy", false, "Exit with code 2 if a generated password violates the --policy-* thresholds")
+	rootCmd.Flags().IntVar(&policyMinLength, "policy-min-length", 0, "Policy: minimum password length")
+	rootCmd.Flags().Float64Var(&policyMinEntropy, "policy-min-entropy", 0, "Policy: minimum entropy in bits")
+	rootCmd.Flags().StringSliceVar(&policyRequire, "policy-require", nil, "Policy: required character sets (upper, lower, numbers, special)")
+	rootCmd.Flags().StringVar(&filterStrength, "filter-strength", "", "Print only passwords at or above this strength (Weak, Moderate, Strong or Excellent)")
+	rootCmd.Flags().BoolVar(&verbose, "verbose", false, "Report extra details on stderr, such as how many passwords --filter-strength left out or weak entropy")
+	rootCmd.Flags().BoolVar(&histogram, "histogram", false, "Print a text histogram of the effective entropy across the batch")
+	rootCmd.Flags().BoolVar(&timestamp, "timestamp", false, "Prefix each password line with the RFC 3339 generation time, for audit trails (text output only)")
+	rootCmd.Flags().BoolVar(&phonetic, "phonetic", false, "Also print the NATO phonetic spelling of each password, for reading it aloud")
+	rootCmd.Flags().BoolVar(&reverse, "reverse", false, "Also print each password reversed (rune by rune), for tools that expect it typed backwards")
+	rootCmd.Flags().BoolVar(&fingerprint, "fingerprint", false, "Also print a short SHA-256 fingerprint of each password to confirm it was copied correctly")
+	rootCmd.Flags().BoolVar(&noPlaintext, "no-plaintext", false, "Do not print the plaintext password (requires --hash)")
+	shareCharsetFlags()
 }
 
 // colorStrength returns the password strength string colorized for CLI output.
@@ -121,3 +843,38 @@ func colorStrength(strength string) string {
 		return strength
 	}
 }
+
+// progressReporter returns a callback that renders a progress percentage on w,
+// along with the interval (in passwords) at which it should be invoked.
+// It returns a nil callback when w is not a terminal so redirected output stays clean.
+func progressReporter(w io.Writer, total int) (func(done, total int), int) {
+	f, ok := w.(*os.File)
+	if !ok || !(isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())) {
+		return nil, 0
+	}
+	lastDone, lastTime := 0, time.Now()
+	report := func(done, total int) {
+		now := time.Now()
+		// Rolling rate over the last reporting interval
+		rate := generator.Stats{Count: done - lastDone, Elapsed: now.Sub(lastTime)}.Throughput()
+		lastDone, lastTime = done, now
+		fmt.Fprintf(w, "\rGenerating: %3d%% (%d/%d, %.0f passwords/s)", done*100/total, done, total, rate)
+		if done == total {
+			fmt.Fprintln(w)
+		}
+	}
+	return report, max(total/100, 1)
+}
+
+// hashPasswords hashes each generated password with algo.
+func hashPasswords(passwords []generator.GeneratedPassword, algo generator.HashAlgo) ([]string, error) {
+	hashes := make([]string, len(passwords))
+	for i, p := range passwords {
+		var err error
+		hashes[i], err = generator.HashPassword(p.Value, algo, generator.HashParams{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
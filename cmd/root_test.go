@@ -0,0 +1,751 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/clipboard"
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/efeaslansoyler/go-passwordgen/internal/manifest"
+	"github.com/efeaslansoyler/go-passwordgen/internal/secretstore"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resetFlags restores every flag of c and its subcommands to its default value,
+// since cobra keeps flag state in package-level variables between executions.
+func resetFlags(t *testing.T, c *cobra.Command) {
+	t.Helper()
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			if err := sv.Replace(nil); err != nil {
+				t.Fatalf("failed to reset flag %s: %v", f.Name, err)
+			}
+		} else if err := f.Value.Set(f.DefValue); err != nil {
+			t.Fatalf("failed to reset flag %s: %v", f.Name, err)
+		}
+		f.Changed = false
+	})
+	for _, sub := range c.Commands() {
+		resetFlags(t, sub)
+	}
+}
+
+// executeRoot runs the root command with args and returns its stdout and stderr.
+func executeRoot(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	resetFlags(t, rootCmd)
+	rootCmd.SilenceUsage = false
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+// TestCopy_Fake checks that --copy hands the exact generated password to the clipboard backend.
+func TestCopy_Fake(t *testing.T) {
+	fake := &clipboard.Fake{}
+	orig := newClipboard
+	newClipboard = func() clipboard.Clipboarder { return fake }
+	t.Cleanup(func() { newClipboard = orig })
+
+	stdout, _, err := executeRoot(t, "--copy", "--quiet", "--length", "16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.TrimSpace(stdout)
+	if len(fake.Copied) != 1 {
+		t.Fatalf("expected 1 clipboard write, got %d", len(fake.Copied))
+	}
+	if fake.Copied[0] != want {
+		t.Errorf("expected clipboard to receive %q, got %q", want, fake.Copied[0])
+	}
+}
+
+// TestFilterStrength checks that --filter-strength prints only qualifying
+// passwords and that --verbose reports how many were left out.
+func TestFilterStrength(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--length", "10", "--special=false", "--upper=false", "--count", "40", "--filter-strength", "Moderate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.Contains(line, "Strength: Weak") {
+			t.Errorf("weak password printed: %s", line)
+		}
+	}
+
+	stdout, stderr, err := executeRoot(t, "--length", "8", "--special=false", "--numbers=false", "--upper=false", "--count", "20", "--quiet", "--filter-strength", "Moderate", "--verbose")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "" {
+		t.Errorf("expected every weak password to be filtered, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Filtered 20 password(s) below Moderate") {
+		t.Errorf("expected the filtered count on stderr, got %q", stderr)
+	}
+
+	if _, _, err := executeRoot(t, "--filter-strength", "great"); err == nil {
+		t.Error("expected an error for an unknown strength label")
+	}
+}
+
+// TestManifest checks that --manifest writes a manifest that verifies under
+// the key, holds each password's fingerprint and never the password itself.
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("auditor key"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "manifest.json")
+	stdout, _, err := executeRoot(t, "--count", "3", "--quiet", "--manifest", path, "--manifest-key-file", keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, err := manifest.Read(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.Verify([]byte("auditor key")); err != nil {
+		t.Errorf("expected the manifest to verify, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	passwords := strings.Fields(stdout)
+	for i, p := range passwords {
+		if i >= len(m.Fingerprints) || mustFingerprint(t, m, p) != m.Fingerprints[i] {
+			t.Errorf("expected fingerprint %d to match password %d", i, i)
+		}
+		if strings.Contains(string(data), p) {
+			t.Errorf("manifest contains the plaintext password %q", p)
+		}
+	}
+	if len(m.Fingerprints) != len(passwords) {
+		t.Errorf("expected %d fingerprints, got %d", len(passwords), len(m.Fingerprints))
+	}
+
+	if _, _, err := executeRoot(t, "--manifest", path); err == nil {
+		t.Error("expected an error for --manifest without a key file")
+	}
+}
+
+// mustFingerprint returns the fingerprint of password in m under the test key.
+func mustFingerprint(t *testing.T, m manifest.Manifest, password string) string {
+	t.Helper()
+	fp, err := m.Fingerprint([]byte("auditor key"), password)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fp
+}
+
+// TestNumbered checks that --numbered prefixes each quiet line with its index
+// and a tab, without strength or entropy text.
+func TestNumbered(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--quiet", "--numbered", "--count", "3", "--length", "16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		index, password, ok := strings.Cut(line, "\t")
+		if !ok || index != strconv.Itoa(i+1) || utf8.RuneCountInString(password) != 16 {
+			t.Errorf("unexpected line %q", line)
+		}
+		if strings.Contains(line, "Strength") || strings.Contains(line, "Entropy") {
+			t.Errorf("line %q contains strength text", line)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "--numbered"); err == nil {
+		t.Error("expected an error for --numbered without --quiet")
+	}
+	for _, format := range []string{"json", "dotenv", "table", "csv-bitwarden"} {
+		if _, _, err := executeRoot(t, "--quiet", "--numbered", "--format", format); err == nil {
+			t.Errorf("expected an error for --numbered with --format %s", format)
+		}
+	}
+}
+
+// TestCountFrom checks that --count-from generates as many passwords as the
+// file says and rejects files that do not hold a single integer in range.
+func TestCountFrom(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return path
+	}
+
+	stdout, _, err := executeRoot(t, "--count-from", write("count", "7\n"), "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 7 {
+		t.Errorf("expected 7 passwords, got %d", len(lines))
+	}
+
+	for _, content := range []string{"", "seven", "3 4", "0", "-2", "1000001"} {
+		if _, _, err := executeRoot(t, "--count-from", write("bad", content)); err == nil {
+			t.Errorf("expected error for count file %q", content)
+		}
+	}
+	if _, _, err := executeRoot(t, "--count-from", filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for a missing count file")
+	}
+}
+
+// TestTargetEntropy checks that --target-entropy picks the shortest length for
+// the selected sets and that --maximize-charset shortens it further.
+func TestTargetEntropy(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--target-entropy", "128", "--special=false", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(strings.TrimSpace(stdout)); got != 22 {
+		t.Errorf("expected 22 alphanumeric characters, got %d", got)
+	}
+	stdout, _, err = executeRoot(t, "--target-entropy", "128", "--special=false", "--maximize-charset", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(strings.TrimSpace(stdout)); got != 20 {
+		t.Errorf("expected 20 characters from every set, got %d", got)
+	}
+
+	if _, _, err := executeRoot(t, "--maximize-charset"); err == nil {
+		t.Error("expected error for --maximize-charset without --target-entropy")
+	}
+	if _, _, err := executeRoot(t, "--target-entropy", "128", "--length", "16"); err == nil {
+		t.Error("expected error for --target-entropy with --length")
+	}
+}
+
+// TestDryRun checks that --dry-run prints "valid" for valid options and fails
+// with the validation error otherwise, without generating a password.
+func TestDryRun(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--dry-run", "--length", "16", "--min-classes", "3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "valid\n" {
+		t.Errorf("expected only \"valid\", got %q", stdout)
+	}
+
+	stdout, stderr, err := executeRoot(t, "--dry-run", "--length", "16", "--min-classes", "5")
+	if err == nil {
+		t.Fatal("expected error for an invalid configuration")
+	}
+	if want := "exceeds the 4 selected character sets"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+	if exitCode(err) == 0 || stdout != "" || strings.Contains(stderr, "Usage:") {
+		t.Errorf("expected a non-zero exit without output or usage, got code %d, stdout %q", exitCode(err), stdout)
+	}
+
+	if _, _, err := executeRoot(t, "--dry-run", "--words", "4", "--separator", "\n"); err == nil {
+		t.Error("expected error for an invalid passphrase separator")
+	}
+
+	// Checks made by the command itself apply to dry runs too.
+	for _, args := range [][]string{
+		{"--hash", "md5"},
+		{"--unique-across-runs", filepath.Join(t.TempDir(), "state"), "--words", "4"},
+		{"--filter-strength", "great"},
+		{"--format", "dotenv", "--export-prefix", "1BAD"},
+		{"--format", "csv-bitwarden", "--hash", "bcrypt"},
+	} {
+		stdout, _, err := executeRoot(t, append([]string{"--dry-run"}, args...)...)
+		if err == nil || strings.Contains(stdout, "valid\n") {
+			t.Errorf("%v: expected an error instead of \"valid\", got %v", args, err)
+		}
+	}
+}
+
+// TestWordlist checks that --wordlist merges a bundled list with a file.
+func TestWordlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "turkish.txt")
+	if err := os.WriteFile(path, []byte("elma\nekmek\nsu\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout, _, err := executeRoot(t, "--words", "3", "--wordlist", "bip39:english,"+path, "--count", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fmt.Sprintf("Entropy: %.2f", 3*math.Log2(2048+3)); !strings.Contains(stdout, want) {
+		t.Errorf("expected %q for the merged list, got:\n%s", want, stdout)
+	}
+
+	if _, _, err := executeRoot(t, "--wordlist", path); err == nil {
+		t.Error("expected error for --wordlist without --words")
+	}
+	if _, _, err := executeRoot(t, "--words", "3", "--wordlist", "bip39:klingon"); err == nil {
+		t.Error("expected error for an unknown bundled list")
+	}
+}
+
+// TestRequireFrom checks that every password has a character from each
+// --require-from group.
+func TestRequireFrom(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--require-from", "!@#", "--require-from", ",.;", "--count", "50", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Fields(stdout) {
+		if !strings.ContainsAny(line, "!@#") || !strings.ContainsAny(line, ",.;") {
+			t.Errorf("password %q misses a required group", line)
+		}
+	}
+}
+
+// TestStore_Fake checks that --store saves the generated password under the
+// --account name and that it requires an account and a single password.
+func TestStore_Fake(t *testing.T) {
+	fake := &secretstore.Fake{}
+	orig := newSecretStore
+	newSecretStore = func() secretstore.Storer { return fake }
+	t.Cleanup(func() { newSecretStore = orig })
+
+	stdout, _, err := executeRoot(t, "--store", "--account", "mail", "--quiet", "--length", "16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.TrimSpace(stdout)
+	if len(fake.Secrets) != 1 {
+		t.Fatalf("expected 1 stored secret, got %d", len(fake.Secrets))
+	}
+	if got := fake.Secrets["mail"]; got != want {
+		t.Errorf("expected account mail to hold %q, got %q", want, got)
+	}
+
+	if _, _, err := executeRoot(t, "--store"); err == nil {
+		t.Error("expected error for --store without --account")
+	}
+	if _, _, err := executeRoot(t, "--store", "--account", "mail", "--count", "2"); err == nil {
+		t.Error("expected error for --store with several passwords")
+	}
+}
+
+// TestEntropyOnly checks that --entropy-only prints a single number matching MaxEntropy.
+func TestEntropyOnly(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--entropy-only", "--length", "20", "--special=false")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) != 1 {
+		t.Fatalf("expected a single value, got %q", stdout)
+	}
+	got, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		t.Fatalf("output is not numeric: %v", err)
+	}
+	want, err := generator.MaxEntropy(generator.PasswordOptions{
+		Length: 20, UseNumbers: true, UseUpper: true, UseLower: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got-want) > 0.005 {
+		t.Errorf("expected %.2f, got %.2f", want, got)
+	}
+}
+
+// TestSeedFile checks that two runs with the same seed file produce the same batch.
+func TestSeedFile(t *testing.T) {
+	seedPath := filepath.Join(t.TempDir(), "seed")
+	if err := os.WriteFile(seedPath, []byte("reproducible fixtures"), 0o600); err != nil {
+		t.Fatalf("failed to write seed file: %v", err)
+	}
+
+	first, stderr, err := executeRoot(t, "--seed-file", seedPath, "--count", "5", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr, "WARNING") {
+		t.Errorf("expected an insecurity warning on stderr, got %q", stderr)
+	}
+	second, _, err := executeRoot(t, "--seed-file", seedPath, "--count", "5", "--quiet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected identical batches, got:\n%s\nand:\n%s", first, second)
+	}
+	if n := len(strings.Fields(first)); n != 5 {
+		t.Errorf("expected 5 passwords, got %d", n)
+	}
+}
+
+// TestPrintConfig checks that --print-config writes the effective options as JSON
+// to stderr, leaves out the seed and round-trips the --require-from groups.
+func TestPrintConfig(t *testing.T) {
+	seed := filepath.Join(t.TempDir(), "seed")
+	if err := os.WriteFile(seed, []byte("fixture-seed"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout, stderr, err := executeRoot(t, "--print-config", "--quiet", "--length", "20", "--max-special", "3", "--seed-file", seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(strings.TrimSpace(stdout)) != 20 {
+		t.Errorf("expected a 20-character password on stdout, got %q", stdout)
+	}
+
+	start := strings.Index(stderr, "{")
+	if start < 0 {
+		t.Fatalf("no JSON config on stderr: %q", stderr)
+	}
+	var config map[string]any
+	if err := json.NewDecoder(strings.NewReader(stderr[start:])).Decode(&config); err != nil {
+		t.Fatalf("invalid JSON config: %v", err)
+	}
+	if config["length"] != float64(20) || config["max_special"] != float64(3) || config["use_upper"] != true {
+		t.Errorf("unexpected config values: %v", config)
+	}
+	for key := range config {
+		if strings.Contains(key, "seed") {
+			t.Errorf("config must not contain seed material, found %q", key)
+		}
+	}
+	if strings.Contains(stderr, "fixture-seed") {
+		t.Error("config leaks the seed contents")
+	}
+
+	_, stderr, err = executeRoot(t, "--print-config", "--require-from", "!@#", "--require-from", "xyz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var opts generator.PasswordOptions
+	if err := json.NewDecoder(strings.NewReader(stderr)).Decode(&opts); err != nil {
+		t.Fatalf("invalid JSON config: %v", err)
+	}
+	if want := (generator.CharGroups{[]rune("!@#"), []rune("xyz")}); !reflect.DeepEqual(opts.RequireFromGroups, want) {
+		t.Errorf("expected the config to round-trip the groups %q, got %q", want, opts.RequireFromGroups)
+	}
+}
+
+// TestRetries checks that --retries reports regenerations for tightly constrained options.
+func TestRetries(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--retries", "--special=false", "--upper=false", "--lower=false",
+		"--length", "8", "--min-distinct", "8", "--count", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var retries int
+	for _, line := range strings.Split(stdout, "\n") {
+		if rest, ok := strings.CutPrefix(line, "Retries: "); ok {
+			if retries, err = strconv.Atoi(rest); err != nil {
+				t.Fatalf("invalid retries line %q", line)
+			}
+		}
+	}
+	// Eight distinct digits out of ten occur in under 2% of candidates.
+	if retries == 0 {
+		t.Errorf("expected a positive retry count, got output:\n%s", stdout)
+	}
+}
+
+// TestUniqueAcrossRuns checks that a second seeded run, which would otherwise
+// repeat the first, skips the password recorded in the state file.
+func TestUniqueAcrossRuns(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed")
+	if err := os.WriteFile(seed, []byte("fixture-seed"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state := filepath.Join(dir, "state")
+
+	first, _, err := executeRoot(t, "--quiet", "--seed-file", seed, "--unique-across-runs", state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, _, err := executeRoot(t, "--quiet", "--seed-file", seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again != first {
+		t.Fatalf("expected the seed to reproduce %q, got %q", first, again)
+	}
+
+	second, _, err := executeRoot(t, "--quiet", "--seed-file", seed, "--unique-across-runs", state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second == first {
+		t.Errorf("second run repeated the recorded password %q", first)
+	}
+	data, err := os.ReadFile(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), strings.TrimSpace(first)) {
+		t.Error("state file contains a plaintext password")
+	}
+}
+
+// TestFingerprint checks that --fingerprint prints the fingerprint of each password in quiet mode.
+func TestFingerprint(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--fingerprint", "--quiet", "--count", "3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 || fields[1] != generator.Fingerprint(fields[0]) {
+			t.Errorf("expected password and fingerprint, got %q", line)
+		}
+	}
+}
+
+// TestCaseInsensitive checks that --case-insensitive picks lowercase by default and
+// uppercase when --upper is given.
+func TestCaseInsensitive(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--case-insensitive", "--quiet", "--length", "40")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.ContainsAny(stdout, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		t.Errorf("expected lowercase only, got %q", stdout)
+	}
+	stdout, _, err = executeRoot(t, "--case-insensitive", "--upper", "--quiet", "--length", "40")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.ContainsAny(stdout, "abcdefghijklmnopqrstuvwxyz") {
+		t.Errorf("expected uppercase only, got %q", stdout)
+	}
+}
+
+// TestCountDistinctCheck checks that a tiny keyspace triggers the duplicate warning
+// and that a large one does not.
+func TestCountDistinctCheck(t *testing.T) {
+	_, stderr, err := executeRoot(t, "--count-distinct-check", "--quiet", "--special=false", "--upper=false",
+		"--lower=false", "--length", "1", "--count", "50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr, "of 50 passwords are distinct") {
+		t.Errorf("expected a duplicate warning, got %q", stderr)
+	}
+
+	_, stderr, err = executeRoot(t, "--count-distinct-check", "--quiet", "--length", "16", "--count", "50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("expected no warning, got %q", stderr)
+	}
+}
+
+// TestPhonetic checks that --phonetic prints the spelling below each password.
+func TestPhonetic(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--phonetic", "--count", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(stdout, "\n")
+	for i, line := range lines {
+		value, ok := strings.CutPrefix(line, "Password ")
+		if !ok {
+			continue
+		}
+		value = strings.SplitN(value, ": ", 2)[1]
+		value = value[:strings.LastIndex(value, " (Strength")]
+		if want := "  Phonetic: " + generator.Phonetic(value); lines[i+1] != want {
+			t.Errorf("expected %q after %q, got %q", want, line, lines[i+1])
+		}
+	}
+
+	if _, _, err := executeRoot(t, "--phonetic", "--hash", "bcrypt", "--no-plaintext"); err == nil {
+		t.Error("expected error combining --phonetic with --no-plaintext")
+	}
+}
+
+// TestQAMarker checks that --qa-marker without a value embeds "Qa" and that a
+// custom marker is used as given.
+func TestQAMarker(t *testing.T) {
+	for _, tt := range []struct{ flag, marker string }{{"--qa-marker", "Qa"}, {"--qa-marker=TEST", "TEST"}} {
+		stdout, _, err := executeRoot(t, tt.flag, "--quiet", "--length", "10")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		value := strings.TrimSpace(stdout)
+		if !strings.Contains(value, tt.marker) || len(value) != 10+len(tt.marker) {
+			t.Errorf("%s: expected marker %q on top of 10 characters, got %q", tt.flag, tt.marker, value)
+		}
+	}
+}
+
+// TestReverse checks that --reverse prints the reversed password next to it in
+// quiet mode, keeping multi-byte runes intact.
+func TestReverse(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--reverse", "--quiet", "--extra-chars", "äé🔑", "--length", "30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := strings.Split(strings.TrimSpace(stdout), "\t")
+	if len(fields) != 2 || fields[1] != generator.ReverseRunes(fields[0]) || !utf8.ValidString(fields[1]) {
+		t.Errorf("expected password and its reversal, got %q", stdout)
+	}
+
+	if _, _, err := executeRoot(t, "--reverse", "--hash", "bcrypt", "--no-plaintext"); err == nil {
+		t.Error("expected error combining --reverse with --no-plaintext")
+	}
+}
+
+// TestConcat checks that --concat prints a single secret made of every chunk.
+func TestConcat(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--concat", "--count", "4", "--length", "16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(stdout, "Password "); n != 1 {
+		t.Fatalf("expected a single password, got %d:\n%s", n, stdout)
+	}
+	value := strings.Fields(strings.SplitN(stdout, ": ", 2)[1])[0]
+	if len(value) != 64 {
+		t.Errorf("expected 64 characters, got %q", value)
+	}
+}
+
+// TestTimestamp checks that --timestamp prefixes each line with an RFC 3339 time
+// in both text modes.
+func TestTimestamp(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--timestamp", "--quiet", "--count", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		stamp, _, ok := strings.Cut(line, "\t")
+		if _, err := time.Parse(time.RFC3339, stamp); !ok || err != nil {
+			t.Errorf("line %q does not start with an RFC 3339 timestamp", line)
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "--timestamp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stamp, rest, _ := strings.Cut(stdout, " ")
+	if _, err := time.Parse(time.RFC3339, stamp); err != nil || !strings.HasPrefix(rest, "Password 1: ") {
+		t.Errorf("expected a timestamped password line, got %q", stdout)
+	}
+}
+
+// TestJSONDiagnostics checks that --format json reports the passwords and a
+// diagnostic for a weak configuration, without warnings on stderr, and that
+// text output prints the weak entropy warning only with --verbose.
+func TestJSONDiagnostics(t *testing.T) {
+	stdout, stderr, err := executeRoot(t, "--format", "json", "--special=false", "--upper=false",
+		"--lower=false", "--length", "4", "--count", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report passwordReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(report.Passwords) != 2 || len(report.Passwords[0].Value) != 4 {
+		t.Errorf("expected 2 passwords of 4 digits, got %+v", report.Passwords)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Code != generator.DiagnosticWeakEntropy {
+		t.Errorf("expected a weak entropy diagnostic, got %+v", report.Diagnostics)
+	}
+	if stderr != "" {
+		t.Errorf("expected no warnings on stderr, got %q", stderr)
+	}
+
+	_, stderr, err = executeRoot(t, "--special=false", "--upper=false", "--lower=false", "--length", "12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("expected no warnings on stderr without --verbose, got %q", stderr)
+	}
+	_, stderr, err = executeRoot(t, "--special=false", "--upper=false", "--lower=false", "--length", "4", "--verbose")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr, "Warning: each password has only 13.29 bits of entropy") {
+		t.Errorf("expected a weak entropy warning on stderr with --verbose, got %q", stderr)
+	}
+}
+
+// TestCharsetSpec checks that --charset-spec replaces the set flags and rejects
+// unknown classes.
+func TestCharsetSpec(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--charset-spec", "[:digit:]", "--quiet", "--length", "30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value := strings.TrimSpace(stdout); strings.Trim(value, "0123456789") != "" || len(value) != 30 {
+		t.Errorf("expected 30 digits, got %q", value)
+	}
+	if _, _, err := executeRoot(t, "--charset-spec", "[:nope:]"); err == nil {
+		t.Error("expected error for an unknown class")
+	}
+}
+
+// TestNoShift checks that --no-shift drops uppercase letters and shifted symbols
+// and that an explicit --upper is rejected.
+func TestNoShift(t *testing.T) {
+	stdout, _, err := executeRoot(t, "--no-shift", "--quiet", "--length", "40", "--count", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.ContainsAny(stdout, "ABCDEFGHIJKLMNOPQRSTUVWXYZ~!@#$%^&*()_+{}|:\"<>?") {
+		t.Errorf("expected no shifted characters, got %q", stdout)
+	}
+	if _, _, err := executeRoot(t, "--no-shift", "--upper"); err == nil {
+		t.Error("expected error combining --no-shift with --upper")
+	}
+}
+
+// TestMinLengthWarning checks that a short length warns on stderr unless
+// --no-warn or --quiet is given, and that a long one does not.
+func TestMinLengthWarning(t *testing.T) {
+	tests := []struct {
+		args []string
+		warn bool
+	}{
+		{[]string{"--length", "8"}, true},
+		{[]string{"--length", "8", "--no-warn"}, false},
+		{[]string{"--length", "8", "--quiet"}, false},
+		{[]string{"--length", "16"}, false},
+	}
+	for _, tt := range tests {
+		_, stderr, err := executeRoot(t, tt.args...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Contains(stderr, "shorter than 12 characters"); got != tt.warn {
+			t.Errorf("%v: expected warning %v, got stderr %q", tt.args, tt.warn, stderr)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/efeaslansoyler/go-passwordgen/internal/ratelimit"
+	"github.com/spf13/cobra"
+)
+
+// maxServeCount caps the number of passwords a single request may ask for.
+const maxServeCount = 100
+
+// serveCmd serves generated passwords over HTTP.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve generated passwords over HTTP",
+	Long: `Serve generated passwords as JSON on GET /generate. The query parameters
+length (default 16) and count (default 1, at most 100) select the batch; every
+character set is enabled. Each client IP address has its own --rate limit;
+requests beyond it receive 429 Too Many Requests. Bind to a local address or
+put the server behind TLS: passwords are sent in the response body.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveRate < 0 {
+			return errors.New("--rate cannot be negative")
+		}
+		var limiter ratelimit.Limiter
+		if serveRate > 0 {
+			limiter = ratelimit.NewPerKey(serveRate, serveBurst)
+		}
+		server := &http.Server{
+			Addr:              serveAddr,
+			Handler:           newServeHandler(limiter),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Listening on http://%s/generate\n", serveAddr)
+		return server.ListenAndServe()
+	},
+}
+
+// newServeHandler returns the HTTP handler of the serve command. Requests to
+// /generate that limiter refuses for their client receive 429; a nil limiter
+// allows every request.
+func newServeHandler(limiter ratelimit.Limiter) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /generate", func(w http.ResponseWriter, r *http.Request) {
+		if limiter != nil && !limiter.Allow(clientKey(r)) {
+			writeServeResult(w, http.StatusTooManyRequests, batchResult{Error: "rate limit exceeded"})
+			return
+		}
+		opts, err := serveOptions(r)
+		if err != nil {
+			writeServeResult(w, http.StatusBadRequest, batchResult{Error: err.Error()})
+			return
+		}
+		passwords, err := generator.GeneratePassword(opts)
+		if err != nil {
+			writeServeResult(w, http.StatusBadRequest, batchResult{Error: err.Error()})
+			return
+		}
+		result := batchResult{Passwords: make([]batchPassword, len(passwords))}
+		for i, p := range passwords {
+			result.Passwords[i] = batchPassword{Value: p.Value, Strength: p.Strength, Entropy: p.Entropy}
+		}
+		writeServeResult(w, http.StatusOK, result)
+	})
+	return mux
+}
+
+// clientKey identifies the client of r for rate limiting by its IP address,
+// without the port, which changes between connections. Headers such as
+// X-Forwarded-For are ignored because any client can set them.
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// serveOptions returns the generation options selected by the query of r.
+func serveOptions(r *http.Request) (generator.PasswordOptions, error) {
+	opts := generator.PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           1,
+	}
+	query := r.URL.Query()
+	for name, dst := range map[string]*int{"length": &opts.Length, "count": &opts.Count} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = n
+		}
+	}
+	if opts.Count > maxServeCount {
+		return opts, fmt.Errorf("count cannot exceed %d", maxServeCount)
+	}
+	return opts, nil
+}
+
+// writeServeResult writes result as JSON with the given status code. Responses
+// contain secrets, so they must not be cached.
+func writeServeResult(w http.ResponseWriter, status int, result batchResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	// The client may have gone away; there is no one left to report to.
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// Serve flag variables.
+var (
+	serveAddr  string  // Address to listen on
+	serveRate  float64 // Requests per second each client may make on /generate (0 = unlimited)
+	serveBurst int     // Requests allowed at once before the rate applies
+)
+
+// init registers the serve command and its flags.
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().Float64Var(&serveRate, "rate", 5, "Requests per second each client IP may make on /generate (0 = unlimited)")
+	serveCmd.Flags().IntVar(&serveBurst, "burst", 10, "Requests allowed at once before --rate applies")
+}
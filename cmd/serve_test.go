@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/ratelimit"
+)
+
+// getGenerate sends GET /generate with the given query to handler.
+func getGenerate(t *testing.T, handler http.Handler, query string) (int, batchResult) {
+	t.Helper()
+	return getGenerateFrom(t, handler, query, "192.0.2.1:1234")
+}
+
+// getGenerateFrom sends GET /generate with the given query to handler as if
+// from the client at remoteAddr.
+func getGenerateFrom(t *testing.T, handler http.Handler, query, remoteAddr string) (int, batchResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/generate"+query, nil)
+	req.RemoteAddr = remoteAddr
+	handler.ServeHTTP(rec, req)
+	var result batchResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON response: %v\n%s", err, rec.Body)
+	}
+	return rec.Code, result
+}
+
+// TestServe_Generate checks the JSON response and that invalid queries are rejected.
+func TestServe_Generate(t *testing.T) {
+	handler := newServeHandler(nil)
+	code, result := getGenerate(t, handler, "?length=20&count=3")
+	if code != http.StatusOK || len(result.Passwords) != 3 || len(result.Passwords[0].Value) != 20 {
+		t.Errorf("expected 3 passwords of length 20, got %d %+v", code, result)
+	}
+	for _, query := range []string{"?length=x", "?count=1000", "?length=2"} {
+		if code, result := getGenerate(t, handler, query); code != http.StatusBadRequest || result.Error == "" {
+			t.Errorf("%s: expected 400 with an error, got %d %+v", query, code, result)
+		}
+	}
+}
+
+// TestServe_RateLimit checks that rapid requests from one client eventually
+// receive 429 while another client, even on a new port, is limited separately.
+func TestServe_RateLimit(t *testing.T) {
+	handler := newServeHandler(ratelimit.NewPerKey(0.001, 5))
+	for i := range 5 {
+		if code, _ := getGenerate(t, handler, ""); code != http.StatusOK {
+			t.Fatalf("request %d within the burst got %d", i+1, code)
+		}
+	}
+	if code, result := getGenerate(t, handler, ""); code != http.StatusTooManyRequests || result.Passwords != nil {
+		t.Errorf("expected 429 without passwords, got %d %+v", code, result)
+	}
+	if code, _ := getGenerateFrom(t, handler, "", "192.0.2.1:5678"); code != http.StatusTooManyRequests {
+		t.Errorf("expected the same IP on another port to be limited, got %d", code)
+	}
+	if code, _ := getGenerateFrom(t, handler, "", "198.51.100.7:1234"); code != http.StatusOK {
+		t.Errorf("expected another client to be allowed, got %d", code)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// splitCmd splits a password read from stdin into Shamir shares.
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split a password from stdin into Shamir secret shares",
+	Long: `Read a password line from stdin and split it with Shamir's Secret Sharing
+into --shares hex-encoded shares, one per line, any --threshold of which
+reconstruct it with the combine command. Fewer shares reveal nothing about the
+password. Store each share with a different custodian.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := readPasswordLine(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		shares, err := generator.SplitSecret(password, splitShares, splitThreshold)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for _, share := range shares {
+			fmt.Fprintln(out, hex.EncodeToString(share))
+		}
+		return nil
+	},
+}
+
+// combineCmd reconstructs a password from Shamir shares read from stdin.
+var combineCmd = &cobra.Command{
+	Use:   "combine",
+	Short: "Reconstruct a password from Shamir shares on stdin",
+	Long: `Read hex-encoded shares produced by the split command from stdin, one per
+line, and print the reconstructed password. At least as many shares as the
+threshold used to split are needed; with fewer, the output is meaningless.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var shares [][]byte
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			share, err := hex.DecodeString(line)
+			if err != nil {
+				return fmt.Errorf("share %d is not valid hex: %w", len(shares)+1, err)
+			}
+			shares = append(shares, share)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read shares: %w", err)
+		}
+		password, err := generator.CombineShares(shares)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), password)
+		return nil
+	},
+}
+
+// Split flag variables.
+var (
+	splitShares    int // Number of shares to produce
+	splitThreshold int // Number of shares needed to reconstruct the password
+)
+
+// init registers the split and combine commands and their flags.
+func init() {
+	rootCmd.AddCommand(splitCmd, combineCmd)
+	splitCmd.Flags().IntVarP(&splitShares, "shares", "n", 5, "Number of shares to produce (at most 255)")
+	splitCmd.Flags().IntVarP(&splitThreshold, "threshold", "t", 3, "Number of shares needed to reconstruct the password")
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSplitCombine checks that shares printed by split are turned back into the
+// password by combine.
+func TestSplitCombine(t *testing.T) {
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	rootCmd.SetIn(strings.NewReader("x8#Kq2!vLm9@\n"))
+	stdout, _, err := executeRoot(t, "split", "--shares", "4", "--threshold", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shares := strings.Fields(stdout)
+	if len(shares) != 4 {
+		t.Fatalf("expected 4 shares, got %d", len(shares))
+	}
+
+	rootCmd.SetIn(strings.NewReader(shares[3] + "\n\n" + shares[1] + "\n"))
+	stdout, _, err = executeRoot(t, "combine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.TrimSpace(stdout); got != "x8#Kq2!vLm9@" {
+		t.Errorf("expected the original password, got %q", got)
+	}
+
+	rootCmd.SetIn(strings.NewReader("zz\n" + shares[0] + "\n"))
+	if _, _, err := executeRoot(t, "combine"); err == nil {
+		t.Error("expected error for a share that is not hex")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// storyCmd generates memorable adjective-noun-verb passwords.
+var storyCmd = &cobra.Command{
+	Use:   "story",
+	Short: "Generate memorable passwords like RedFoxJumps7",
+	Long: `Generate memorable "story" passwords from a small grammar: an adjective, a
+noun and a verb from bundled 32-word lists, followed by random digits and an
+optional special character. The words give only 15 bits of entropy, so add
+digits when the password needs real strength.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passwords, err := generator.GenerateStory(generator.StoryOptions{
+			Digits: storyDigits,
+			Symbol: storySymbol,
+			Count:  storyCount,
+		})
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for i, p := range passwords {
+			fmt.Fprintf(out, "Password %d: %s (Strength: %s, Entropy: %.2f)\n",
+				i+1, p.Value, colorStrength(p.Strength), p.Entropy)
+		}
+		return nil
+	},
+}
+
+// Story flag variables.
+var (
+	storyDigits int  // Number of digits appended after the words
+	storySymbol bool // Append a special character
+	storyCount  int  // Number of passwords to generate
+)
+
+// init registers the story command and its flags.
+func init() {
+	rootCmd.AddCommand(storyCmd)
+	storyCmd.Flags().IntVarP(&storyDigits, "digits", "d", 1, "Number of random digits appended after the words")
+	storyCmd.Flags().BoolVarP(&storySymbol, "symbol", "s", false, "Append a random special character")
+	storyCmd.Flags().IntVarP(&storyCount, "count", "c", 1, "Number of passwords to generate")
+}
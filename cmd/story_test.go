@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestStory checks the shape of the story command output.
+func TestStory(t *testing.T) {
+	stdout, _, err := executeRoot(t, "story", "--digits", "3", "--count", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shape := regexp.MustCompile(`^Password \d: ([A-Z][a-z]+){3}[0-9]{3} \(Strength: `)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !shape.MatchString(line) {
+			t.Errorf("unexpected line %q", line)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "story", "--count", "0"); err == nil {
+		t.Error("expected error for a zero count")
+	}
+}
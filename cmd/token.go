@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/efeaslansoyler/go-passwordgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// tokenCmd generates random tokens such as API keys or IDs.
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Generate random tokens in hex, base64url or base58",
+	Long: `Generate tokens from random bytes, such as API keys or IDs, encoded as
+hex, unpadded URL-safe base64 or base58 with the Bitcoin alphabet (no 0, O, I
+or l). Each token has 8 bits of entropy per byte.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tokenCount < 1 {
+			return errors.New("count must be greater than 0")
+		}
+		enc, err := generator.ParseTokenEncoding(tokenEncoding)
+		if err != nil {
+			return err
+		}
+		for range tokenCount {
+			token, err := generator.GenerateToken(tokenBytes, enc)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+		}
+		return nil
+	},
+}
+
+// Token flag variables.
+var (
+	tokenBytes    int    // Number of random bytes in each token
+	tokenEncoding string // Token encoding name
+	tokenCount    int    // Number of tokens to generate
+)
+
+// init registers the token command and its flags.
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	names := make([]string, len(generator.TokenEncodings))
+	for i, e := range generator.TokenEncodings {
+		names[i] = e.String()
+	}
+	tokenCmd.Flags().IntVarP(&tokenBytes, "bytes", "b", 32, fmt.Sprintf("Number of random bytes in each token (1 to %d)", generator.MaxTokenBytes))
+	tokenCmd.Flags().StringVarP(&tokenEncoding, "encoding", "e", generator.TokenBase58.String(), "Token encoding ("+strings.Join(names, ", ")+")")
+	tokenCmd.Flags().IntVarP(&tokenCount, "count", "c", 1, "Number of tokens to generate")
+}
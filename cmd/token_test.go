@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// TestToken checks that tokens use the requested encoding and count.
+func TestToken(t *testing.T) {
+	stdout, _, err := executeRoot(t, "token", "--count", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokens := strings.Fields(stdout)
+	if len(tokens) != 5 {
+		t.Fatalf("expected 5 tokens, got %d", len(tokens))
+	}
+	for _, token := range tokens {
+		if strings.ContainsAny(token, "0OIl") {
+			t.Errorf("base58 token %s contains an ambiguous character", token)
+		}
+	}
+
+	stdout, _, err = executeRoot(t, "token", "--encoding", "hex", "--bytes", "8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := hex.DecodeString(strings.TrimSpace(stdout)); err != nil || len(b) != 8 {
+		t.Errorf("expected 8 hex-encoded bytes, got %q", stdout)
+	}
+
+	for _, n := range []string{"0", "1025"} {
+		if _, _, err := executeRoot(t, "token", "--bytes", n); err == nil {
+			t.Errorf("expected error for --bytes %s", n)
+		}
+	}
+
+	if _, _, err := executeRoot(t, "token", "--encoding", "base32"); err == nil {
+		t.Error("expected error for an unknown encoding")
+	}
+	for _, count := range []string{"0", "-1"} {
+		if _, _, err := executeRoot(t, "token", "--count", count); err == nil || !strings.Contains(err.Error(), "count must be greater than 0") {
+			t.Errorf("expected a count error for --count %s, got %v", count, err)
+		}
+	}
+}
@@ -1,16 +1,19 @@
 module github.com/efeaslansoyler/go-passwordgen
 
-go 1.24.2
+go 1.25.3
 
 require (
 	github.com/fatih/color v1.18.0
+	github.com/hashicorp/vault v1.21.4
+	github.com/mattn/go-isatty v0.0.20
 	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
+	github.com/tyler-smith/go-bip39 v1.1.0
+	golang.org/x/crypto v0.46.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	golang.org/x/sys v0.40.0 // indirect
 )
@@ -0,0 +1,65 @@
+// Package clipboard copies text to the system clipboard.
+//
+// The backend is selected at build time: pbcopy on macOS, clip on Windows and
+// wl-copy, xclip or xsel on Linux and the BSDs. Other platforms get a backend
+// that always returns ErrUnsupported.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned when no clipboard backend is available.
+var ErrUnsupported = errors.New("clipboard is not supported on this platform")
+
+// Clipboarder writes text to a clipboard.
+type Clipboarder interface {
+	Copy(text string) error
+}
+
+// New returns the clipboard backend for the current platform.
+func New() Clipboarder {
+	return newPlatform()
+}
+
+// commandClipboard copies text by piping it to an external utility.
+type commandClipboard struct {
+	name string
+	args []string
+}
+
+// Copy runs the clipboard utility with text on its standard input.
+func (c commandClipboard) Copy(text string) error {
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", c.name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// unsupported is the backend for platforms without a clipboard utility.
+type unsupported struct{}
+
+// Copy always returns ErrUnsupported.
+func (unsupported) Copy(string) error {
+	return ErrUnsupported
+}
+
+// Fake records copied text in memory. It is intended for tests.
+type Fake struct {
+	Copied []string // Every value passed to Copy, in order
+	Err    error    // Error returned by Copy, if set
+}
+
+// Copy records text and returns f.Err.
+func (f *Fake) Copy(text string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Copied = append(f.Copied, text)
+	return nil
+}
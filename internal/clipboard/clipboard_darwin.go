@@ -0,0 +1,6 @@
+package clipboard
+
+// newPlatform returns a backend using pbcopy.
+func newPlatform() Clipboarder {
+	return commandClipboard{name: "pbcopy"}
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !windows && !linux && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package clipboard
+
+// newPlatform returns a backend that always reports ErrUnsupported.
+func newPlatform() Clipboarder {
+	return unsupported{}
+}
@@ -0,0 +1,26 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly
+
+package clipboard
+
+import (
+	"os"
+	"os/exec"
+)
+
+// newPlatform returns a backend using the first available clipboard utility:
+// wl-copy on Wayland, then xclip and xsel on X11.
+func newPlatform() Clipboarder {
+	candidates := []commandClipboard{
+		{name: "xclip", args: []string{"-selection", "clipboard"}},
+		{name: "xsel", args: []string{"--clipboard", "--input"}},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([]commandClipboard{{name: "wl-copy"}}, candidates...)
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c.name); err == nil {
+			return c
+		}
+	}
+	return unsupported{}
+}
@@ -0,0 +1,6 @@
+package clipboard
+
+// newPlatform returns a backend using clip.exe.
+func newPlatform() Clipboarder {
+	return commandClipboard{name: "clip"}
+}
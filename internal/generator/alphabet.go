@@ -0,0 +1,44 @@
+package generator
+
+import "errors"
+
+// CouponAlphabet is the lowercase Crockford base32 alphabet. It leaves out
+// i, l, o and u, so codes are easy to read aloud and type by hand.
+const CouponAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// GenerateFromAlphabet generates count strings of length characters, each drawn
+// uniformly from alphabet with a cryptographically secure random source.
+// Unlike GeneratePassword, no character class requirements are applied.
+func GenerateFromAlphabet(alphabet string, length, count int) ([]string, error) {
+	runes := []rune(alphabet)
+	if len(runes) == 0 {
+		return nil, errors.New("alphabet is empty")
+	}
+	seen := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		if seen[r] {
+			return nil, errors.New("alphabet contains duplicate characters")
+		}
+		seen[r] = true
+	}
+	if length < 1 {
+		return nil, errors.New("length must be greater than 0")
+	}
+	if count < 1 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	codes := make([]string, count)
+	for i := range codes {
+		code := make([]rune, length)
+		for j := range code {
+			n, err := secureRandomInt(len(runes))
+			if err != nil {
+				return nil, err
+			}
+			code[j] = runes[n]
+		}
+		codes[i] = string(code)
+	}
+	return codes, nil
+}
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateFromAlphabet checks that output only uses the alphabet and has the requested shape.
+func TestGenerateFromAlphabet(t *testing.T) {
+	codes, err := GenerateFromAlphabet(CouponAlphabet, 8, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != 50 {
+		t.Fatalf("expected 50 codes, got %d", len(codes))
+	}
+	for _, code := range codes {
+		if len(code) != 8 {
+			t.Errorf("expected length 8, got %d", len(code))
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(CouponAlphabet, r) {
+				t.Errorf("code %s contains %q outside the alphabet", code, r)
+			}
+		}
+	}
+
+	if _, err := GenerateFromAlphabet("aab", 8, 1); err == nil {
+		t.Error("expected error for duplicate characters")
+	}
+	if _, err := GenerateFromAlphabet("", 8, 1); err == nil {
+		t.Error("expected error for empty alphabet")
+	}
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+)
+
+// minRepeatLength is the shortest substring reported as a repeated block.
+const minRepeatLength = 3
+
+// Analysis holds a detailed strength analysis of a password.
+type Analysis struct {
+	BaseEntropy   float64 // Charset-based entropy in bits, as returned by PasswordEntropy
+	Penalty       float64 // Bits subtracted from BaseEntropy for detected weaknesses
+	Entropy       float64 // Effective entropy in bits (BaseEntropy minus Penalty)
+	Strength      string  // Strength label for Entropy
+	RepeatedBlock string  // Longest block repeated in the password, if any
+
+	// CharFrequency counts how often each rune occurs in the password, to spot
+	// overrepresented characters such as the a's in "aaaa1!Ab".
+	CharFrequency map[rune]int
+}
+
+// AnalyzePassword analyzes password beyond the plain charset-based entropy.
+// A block of at least three characters that occurs more than once, such as
+// "ab12" in "ab12ab12", adds nothing an attacker has to guess, so every extra
+// occurrence is subtracted from the entropy.
+// PasswordEntropy is unaffected and keeps reporting the unpenalized value.
+func AnalyzePassword(password string) (Analysis, error) {
+	base, _, err := PasswordEntropy(password)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	runes := []rune(password)
+	perChar := base / float64(len(runes))
+
+	a := Analysis{BaseEntropy: base, CharFrequency: make(map[rune]int)}
+	for _, r := range runes {
+		a.CharFrequency[r]++
+	}
+	if block, occurrences := longestRepeatedBlock(runes, minRepeatLength); occurrences > 1 {
+		a.RepeatedBlock = block
+		a.Penalty += float64(occurrences-1) * float64(len([]rune(block))) * perChar
+	}
+
+	a.Entropy = max(base-a.Penalty, 0)
+	a.Strength = classify(a)
+	return a, nil
+}
+
+// CompareStrength compares the strength of passwords a and b by their
+// effective entropy as computed by AnalyzePassword, so penalized weaknesses
+// count against a password. It returns -1 if a is weaker than b, 0 if both are
+// equally strong and +1 if a is stronger.
+func CompareStrength(a, b string) (int, error) {
+	analysisA, err := AnalyzePassword(a)
+	if err != nil {
+		return 0, err
+	}
+	analysisB, err := AnalyzePassword(b)
+	if err != nil {
+		return 0, err
+	}
+	return cmp.Compare(analysisA.Entropy, analysisB.Entropy), nil
+}
+
+// longestRepeatedBlock returns the longest substring of at least minLen runes
+// that occurs more than once without overlapping, and its number of
+// non-overlapping occurrences; among blocks of that length it returns the
+// first. It returns ("", 0) if there is none.
+//
+// A repeat of some length implies one of every shorter length (the prefixes of
+// both occurrences), so the length is found by binary search, each step
+// comparing rolling hashes of every window in linear time.
+func longestRepeatedBlock(runes []rune, minLen int) (string, int) {
+	lo, hi := minLen, len(runes)/2
+	for lo <= hi {
+		if size := lo + (hi-lo)/2; firstRepeatedWindow(runes, size) >= 0 {
+			lo = size + 1
+		} else {
+			hi = size - 1
+		}
+	}
+	if hi < minLen {
+		return "", 0
+	}
+	start := firstRepeatedWindow(runes, hi)
+	block := runes[start : start+hi]
+	return string(block), countNonOverlapping(runes, block)
+}
+
+// Rolling hash parameters: the Mersenne prime 2^61-1 as the modulus, and the
+// base windows are hashed in.
+const (
+	hashModulus = 1<<61 - 1
+	hashBase    = 1_000_003
+)
+
+// mulMod returns a*b mod hashModulus for a, b < hashModulus.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	r := (hi<<3 | lo>>61) + lo&hashModulus
+	if r >= hashModulus {
+		r -= hashModulus
+	}
+	return r
+}
+
+// firstRepeatedWindow returns the start of the first window of size runes that
+// also occurs elsewhere in runes without overlapping it, or -1 if there is
+// none. Windows are grouped by rolling hash and confirmed by comparing runes.
+func firstRepeatedWindow(runes []rune, size int) int {
+	if size < 1 || 2*size > len(runes) {
+		return -1
+	}
+	var pow, h uint64 = 1, 0
+	for range size {
+		pow = mulMod(pow, hashBase)
+	}
+	hashes := make([]uint64, len(runes)-size+1)
+	first := make(map[uint64]int, len(hashes))
+	last := make(map[uint64]int, len(hashes))
+	for i, r := range runes {
+		h = (mulMod(h, hashBase) + uint64(r)) % hashModulus
+		if i >= size {
+			h = (h + hashModulus - mulMod(uint64(runes[i-size]), pow)) % hashModulus
+		}
+		if start := i - size + 1; start >= 0 {
+			hashes[start] = h
+			if _, ok := first[h]; !ok {
+				first[h] = start
+			}
+			last[h] = start
+		}
+	}
+	// A hash collision can only hide a repeat, never invent one.
+	for i, h := range hashes {
+		a, b := first[h], last[h]
+		if b-a < size {
+			continue
+		}
+		if block := runes[a : a+size]; slices.Equal(block, runes[b:b+size]) && slices.Equal(block, runes[i:i+size]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// countNonOverlapping counts the non-overlapping occurrences of sub in runes.
+func countNonOverlapping(runes, sub []rune) int {
+	count := 0
+	for i := 0; i+len(sub) <= len(runes); {
+		if slices.Equal(runes[i:i+len(sub)], sub) {
+			count++
+			i += len(sub)
+		} else {
+			i++
+		}
+	}
+	return count
+}
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAnalyzePassword_RepeatedBlock checks that a repeated block is detected and penalized.
+func TestAnalyzePassword_RepeatedBlock(t *testing.T) {
+	a, err := AnalyzePassword("ab12ab12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.RepeatedBlock != "ab12" {
+		t.Errorf("expected repeated block %q, got %q", "ab12", a.RepeatedBlock)
+	}
+	// The second "ab12" adds nothing, so half of the base entropy remains.
+	if math.Abs(a.Entropy-a.BaseEntropy/2) > 1e-9 {
+		t.Errorf("expected entropy %.2f, got %.2f", a.BaseEntropy/2, a.Entropy)
+	}
+	if base, _, _ := PasswordEntropy("ab12ab12"); base != a.BaseEntropy {
+		t.Errorf("expected PasswordEntropy to stay unpenalized at %.2f, got %.2f", a.BaseEntropy, base)
+	}
+}
+
+// TestAnalyzePassword_Clean checks that a random-looking password is not penalized.
+func TestAnalyzePassword_Clean(t *testing.T) {
+	a, err := AnalyzePassword("x8#Kq2!vLm9@")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.RepeatedBlock != "" || a.Penalty != 0 {
+		t.Errorf("expected no repeated block, got %q with penalty %.2f", a.RepeatedBlock, a.Penalty)
+	}
+	if a.Entropy != a.BaseEntropy {
+		t.Errorf("expected entropy %.2f, got %.2f", a.BaseEntropy, a.Entropy)
+	}
+
+	if _, err := AnalyzePassword(""); err == nil {
+		t.Error("expected error for empty password")
+	}
+}
+
+// TestAnalyzePassword_CharFrequency checks that the frequencies sum to the length
+// and count repeated runes.
+func TestAnalyzePassword_CharFrequency(t *testing.T) {
+	pwd := "aaaa1!Abé"
+	a, err := AnalyzePassword(pwd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	total := 0
+	for _, n := range a.CharFrequency {
+		total += n
+	}
+	if total != len([]rune(pwd)) {
+		t.Errorf("expected frequencies to sum to %d, got %d", len([]rune(pwd)), total)
+	}
+	if a.CharFrequency['a'] != 4 || a.CharFrequency['A'] != 1 || a.CharFrequency['é'] != 1 {
+		t.Errorf("unexpected frequencies: %v", a.CharFrequency)
+	}
+}
+
+// TestCompareStrength checks the ordering of clearly different-strength passwords.
+func TestCompareStrength(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"abc", "x8#Kq2!vLm9@", -1},
+		{"x8#Kq2!vLm9@", "abc", 1},
+		{"abcdefgh", "hgfedcba", 0},
+		{"ab1!ab1!ab1!", "qz7$wx3%ty5&", -1}, // same charset, repeated block
+	}
+	for _, tt := range tests {
+		got, err := CompareStrength(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("CompareStrength(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if _, err := CompareStrength("", "abc"); err == nil {
+		t.Error("expected error for an empty password")
+	}
+}
+
+// TestAnalyzePassword_MaxLength checks that passwords of DefaultMaxLength
+// characters are analyzed quickly and that their repeated blocks are found.
+func TestAnalyzePassword_MaxLength(t *testing.T) {
+	passwords, err := GeneratePassword(PasswordOptions{Length: DefaultMaxLength / 2, UseLower: true, UseNumbers: true, Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	half := passwords[0].Value
+	tests := []struct {
+		password string
+		block    string
+	}{
+		{half + half, half},
+		{strings.Repeat("a", DefaultMaxLength), strings.Repeat("a", DefaultMaxLength/2)},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		a, err := AnalyzePassword(tt.password)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("analyzing %d characters took %v", len(tt.password), elapsed)
+		}
+		if a.RepeatedBlock != tt.block {
+			t.Errorf("expected a repeated block of %d characters, got %d", len(tt.block), len(a.RepeatedBlock))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CharClass identifies one of the built-in character sets.
+type CharClass int
+
+const (
+	ClassUpper   CharClass = iota // Uppercase letters A-Z
+	ClassLower                    // Lowercase letters a-z
+	ClassNumbers                  // Digits 0-9
+	ClassSpecial                  // Special characters
+)
+
+// CharClasses lists every built-in character class in display order.
+var CharClasses = []CharClass{ClassUpper, ClassLower, ClassNumbers, ClassSpecial}
+
+// String returns the name of the character class.
+func (c CharClass) String() string {
+	switch c {
+	case ClassUpper:
+		return "upper"
+	case ClassLower:
+		return "lower"
+	case ClassNumbers:
+		return "numbers"
+	case ClassSpecial:
+		return "special"
+	default:
+		return fmt.Sprintf("CharClass(%d)", int(c))
+	}
+}
+
+// CharsetFor returns the characters of the given class, or an empty string for
+// an unknown class.
+func CharsetFor(c CharClass) string {
+	switch c {
+	case ClassUpper:
+		return uppercase
+	case ClassLower:
+		return lowercase
+	case ClassNumbers:
+		return numbers
+	case ClassSpecial:
+		return specialChars
+	default:
+		return ""
+	}
+}
+
+// FullASCIISymbolSet returns every printable ASCII punctuation character, the
+// special set used with PasswordOptions.FullASCIISymbols.
+func FullASCIISymbolSet() string {
+	return fullASCIISymbols
+}
+
+// AmbiguousSet returns the characters PasswordOptions.ExcludeAmbiguous leaves
+// out: opt.AmbiguousChars, or the default set when it is empty.
+func AmbiguousSet(opt PasswordOptions) string {
+	return cmp.Or(opt.AmbiguousChars, defaultAmbiguousChars)
+}
+
+// Charset returns every character opt draws passwords from, after custom
+// sets, exclusions and modes such as NoShift are applied. It does not
+// validate opt.
+func Charset(opt PasswordOptions) string {
+	return string(newPools(opt).charset)
+}
+
+// posixClasses maps the supported POSIX character classes to the built-in
+// classes they select. [:punct:] covers every ASCII punctuation character, so
+// it also selects PasswordOptions.FullASCIISymbols.
+var posixClasses = map[string][]CharClass{
+	"upper": {ClassUpper},
+	"lower": {ClassLower},
+	"digit": {ClassNumbers},
+	"alpha": {ClassUpper, ClassLower},
+	"alnum": {ClassUpper, ClassLower, ClassNumbers},
+	"punct": {ClassSpecial},
+	"graph": {ClassUpper, ClassLower, ClassNumbers, ClassSpecial},
+}
+
+// posixClassPattern matches a single POSIX class such as "[:alnum:]".
+var posixClassPattern = regexp.MustCompile(`\[:([a-z]*):\]`)
+
+// ApplyCharsetSpec selects the character sets of opt from spec, a sequence of
+// POSIX classes such as "[:alnum:][:punct:]", optionally wrapped in a bracket
+// expression as in "[[:alnum:][:punct:]]". It replaces UseUpper, UseLower,
+// UseNumbers and UseSpecialChars; [:punct:] and [:graph:] also enable
+// FullASCIISymbols. Supported classes are upper, lower, digit, alpha, alnum,
+// punct and graph; anything else is an error.
+func ApplyCharsetSpec(opt *PasswordOptions, spec string) error {
+	body := spec
+	if inner, ok := strings.CutPrefix(body, "["); ok && !strings.HasPrefix(body, "[:") {
+		if body, ok = strings.CutSuffix(inner, "]"); !ok {
+			return fmt.Errorf("charset spec %q has an unterminated bracket expression", spec)
+		}
+	}
+	if body == "" {
+		return errors.New("charset spec is empty")
+	}
+
+	selected := make(map[CharClass]bool)
+	for rest := body; rest != ""; {
+		loc := posixClassPattern.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] != 0 {
+			return fmt.Errorf("charset spec %q: expected a class like [:alnum:] at %q", spec, rest)
+		}
+		name := rest[loc[2]:loc[3]]
+		classes, ok := posixClasses[name]
+		if !ok {
+			return fmt.Errorf("charset spec %q: unsupported class [:%s:]", spec, name)
+		}
+		for _, c := range classes {
+			selected[c] = true
+		}
+		rest = rest[loc[1]:]
+	}
+
+	opt.UseUpper = selected[ClassUpper]
+	opt.UseLower = selected[ClassLower]
+	opt.UseNumbers = selected[ClassNumbers]
+	opt.UseSpecialChars = selected[ClassSpecial]
+	opt.FullASCIISymbols = selected[ClassSpecial]
+	return nil
+}
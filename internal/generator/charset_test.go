@@ -0,0 +1,55 @@
+package generator
+
+import "testing"
+
+// TestCharsetFor checks that every class maps to its built-in set and unknown classes to "".
+func TestCharsetFor(t *testing.T) {
+	want := map[CharClass]string{
+		ClassUpper:   uppercase,
+		ClassLower:   lowercase,
+		ClassNumbers: numbers,
+		ClassSpecial: specialChars,
+	}
+	for _, c := range CharClasses {
+		if got := CharsetFor(c); got != want[c] {
+			t.Errorf("CharsetFor(%s) = %q, want %q", c, got, want[c])
+		}
+	}
+	if got := CharsetFor(CharClass(42)); got != "" {
+		t.Errorf("expected empty charset for unknown class, got %q", got)
+	}
+	if got := CharClass(42).String(); got != "CharClass(42)" {
+		t.Errorf("unexpected name for unknown class: %s", got)
+	}
+}
+
+// TestApplyCharsetSpec checks the sets selected by POSIX class specs and that
+// unknown classes and malformed specs are rejected.
+func TestApplyCharsetSpec(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"[:digit:]", numbers},
+		{"[[:digit:]]", numbers},
+		{"[:alpha:]", uppercase + lowercase},
+		{"[:lower:][:digit:]", lowercase + numbers},
+		{"[[:alnum:][:punct:]]", uppercase + lowercase + numbers + fullASCIISymbols},
+		{"[:graph:]", uppercase + lowercase + numbers + fullASCIISymbols},
+	}
+	for _, tt := range tests {
+		opt := PasswordOptions{UseUpper: true, UseSpecialChars: true}
+		if err := ApplyCharsetSpec(&opt, tt.spec); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.spec, err)
+		}
+		if got := buildCharset(opt); got != tt.want {
+			t.Errorf("%s: charset %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "[]", "[:xdigit:]", "[:bogus:]", "digit", "[:digit:]x", "[[:digit:]"} {
+		opt := PasswordOptions{}
+		if err := ApplyCharsetSpec(&opt, spec); err == nil {
+			t.Errorf("expected error for spec %q", spec)
+		}
+	}
+}
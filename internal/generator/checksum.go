@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// checksumAlphabet holds the characters a ModChecksum can produce.
+const checksumAlphabet = numbers + uppercase
+
+// ModChecksum is the default checksum used by GenerateWithCheckChar.
+// Each rune is mapped to a value in [0, 36): digits and letters (case-insensitively)
+// use their position in 0-9A-Z, any other rune its code point modulo 36. The values
+// are weighted by their 1-based position and the sum modulo 36 selects the check
+// character from 0-9A-Z. Swapping two adjacent runes changes the sum by the
+// difference of their values, so every adjacent transposition of distinct digits
+// and letters is detected. Other runes may share a value with a digit or letter
+// (such as '$' and '0'), and swaps of runes further apart are only mostly detected.
+func ModChecksum(body string) rune {
+	sum := 0
+	for i, r := range []rune(body) {
+		v := strings.IndexRune(checksumAlphabet, unicode.ToUpper(r))
+		if v < 0 {
+			v = int(r) % len(checksumAlphabet)
+		}
+		sum += (i + 1) * v
+	}
+	return rune(checksumAlphabet[sum%len(checksumAlphabet)])
+}
+
+// GenerateWithCheckChar generates passwords as GeneratePassword does and appends
+// a check character computed by checksum over each generated body, so the
+// resulting value is one character longer than opt.Length.
+// If checksum is nil, ModChecksum is used. The check character is derived from
+// the body and adds no entropy, so Entropy and Strength describe the body only.
+func GenerateWithCheckChar(opt PasswordOptions, checksum func(string) rune) ([]GeneratedPassword, error) {
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		return nil, err
+	}
+	AddCheckChars(passwords, checksum)
+	return passwords, nil
+}
+
+// AddCheckChars appends a check character computed by checksum to the value of
+// each password in place. If checksum is nil, ModChecksum is used.
+func AddCheckChars(passwords []GeneratedPassword, checksum func(string) rune) {
+	if checksum == nil {
+		checksum = ModChecksum
+	}
+	for i := range passwords {
+		passwords[i].Value += string(checksum(passwords[i].Value))
+	}
+}
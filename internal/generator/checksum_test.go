@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// TestGenerateWithCheckChar checks that the appended character matches the checksum of the body.
+func TestGenerateWithCheckChar(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          12,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           20,
+	}
+	passwords, err := GenerateWithCheckChar(opt, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		runes := []rune(gp.Value)
+		if len(runes) != 13 {
+			t.Fatalf("expected 13 runes including check char, got %d", len(runes))
+		}
+		body, check := string(runes[:12]), runes[12]
+		if want := ModChecksum(body); check != want {
+			t.Errorf("password %s: expected check char %q, got %q", gp.Value, want, check)
+		}
+	}
+
+	custom := func(string) rune { return '#' }
+	passwords, err = GenerateWithCheckChar(opt, custom)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if runes := []rune(gp.Value); runes[len(runes)-1] != '#' {
+			t.Errorf("expected custom check char '#', got %s", gp.Value)
+		}
+	}
+}
+
+// TestModChecksum checks known values and that transpositions change the checksum.
+func TestModChecksum(t *testing.T) {
+	// 1*1 + 2*2 + 3*3 = 14 -> 'E'
+	if got := ModChecksum("123"); got != 'E' {
+		t.Errorf("ModChecksum(\"123\") = %q, want 'E'", got)
+	}
+	if ModChecksum("abc") != ModChecksum("ABC") {
+		t.Error("expected checksum to be case-insensitive")
+	}
+	if ModChecksum("ab12") == ModChecksum("ba12") {
+		t.Error("expected transposition to change the checksum")
+	}
+}
+
+// TestModChecksum_AdjacentTranspositions checks that swapping any two adjacent,
+// distinct digits or letters changes the checksum wherever the pair appears.
+func TestModChecksum_AdjacentTranspositions(t *testing.T) {
+	alphabet := checksumAlphabet + strings.ToLower(uppercase)
+	for _, a := range alphabet {
+		for _, b := range alphabet {
+			if unicode.ToUpper(a) == unicode.ToUpper(b) {
+				continue
+			}
+			for _, prefix := range []string{"", "x", "7Q", "k3P9z"} {
+				body := prefix + string(a) + string(b) + "M4"
+				swapped := prefix + string(b) + string(a) + "M4"
+				if ModChecksum(body) == ModChecksum(swapped) {
+					t.Errorf("ModChecksum(%q) == ModChecksum(%q)", body, swapped)
+				}
+			}
+		}
+	}
+
+	// Runes outside 0-9A-Z may share a value with one: '$' is 36, so 0.
+	if ModChecksum("$0") != ModChecksum("0$") {
+		t.Error("expected '$' and '0' to share a value")
+	}
+}
@@ -0,0 +1,50 @@
+package generator
+
+import "sync"
+
+// Classifier maps a password analysis to a strength label. When it is called,
+// the Strength field of the analysis holds the default label, so a classifier
+// may refine the default rather than replace it entirely.
+type Classifier func(Analysis) string
+
+var (
+	classifierMu sync.RWMutex
+	classifier   Classifier // Custom classifier, or nil for the default entropy bands
+)
+
+// SetClassifier installs c as the package-wide strength classifier used by
+// PasswordEntropy, AnalyzePassword and GeneratePassword, and returns the
+// previous one. Passing nil restores the default entropy bands. It is safe to
+// call concurrently with generation and analysis.
+func SetClassifier(c Classifier) Classifier {
+	classifierMu.Lock()
+	defer classifierMu.Unlock()
+	prev := classifier
+	classifier = c
+	return prev
+}
+
+// DefaultClassifier labels a password by its effective entropy: "Excellent"
+// from 80 bits, "Strong" from 60, "Moderate" from 40, and "Weak" below.
+func DefaultClassifier(a Analysis) string {
+	return strengthLabel(a.Entropy)
+}
+
+// classify returns the strength label of a using the installed classifier.
+func classify(a Analysis) string {
+	a.Strength = DefaultClassifier(a)
+
+	classifierMu.RLock()
+	c := classifier
+	classifierMu.RUnlock()
+	if c == nil {
+		return a.Strength
+	}
+	return c(a)
+}
+
+// classifyEntropy returns the strength label of a password whose only known
+// property is its entropy, without any penalty applied.
+func classifyEntropy(entropy float64) string {
+	return classify(Analysis{BaseEntropy: entropy, Entropy: entropy})
+}
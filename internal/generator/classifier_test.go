@@ -0,0 +1,43 @@
+package generator
+
+import "testing"
+
+// TestSetClassifier checks that a custom classifier overrides every strength label
+// and that restoring nil brings back the default bands.
+func TestSetClassifier(t *testing.T) {
+	var seen []Analysis
+	prev := SetClassifier(func(a Analysis) string {
+		seen = append(seen, a)
+		return "Weak"
+	})
+	t.Cleanup(func() { SetClassifier(prev) })
+
+	pwd := "x8#Kq2!vLm9@zP4$"
+	if _, strength, _ := PasswordEntropy(pwd); strength != "Weak" {
+		t.Errorf("expected PasswordEntropy to use the custom classifier, got %s", strength)
+	}
+	a, err := AnalyzePassword(pwd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Strength != "Weak" {
+		t.Errorf("expected AnalyzePassword to use the custom classifier, got %s", a.Strength)
+	}
+	passwords, err := GeneratePassword(PasswordOptions{Length: 32, UseLower: true, UseUpper: true, Count: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if gp.Strength != "Weak" {
+			t.Errorf("expected GeneratePassword to use the custom classifier, got %s", gp.Strength)
+		}
+	}
+	if len(seen) == 0 || seen[0].Strength != "Excellent" {
+		t.Errorf("expected the classifier to receive the default label, got %+v", seen)
+	}
+
+	SetClassifier(nil)
+	if _, strength, _ := PasswordEntropy(pwd); strength != "Excellent" {
+		t.Errorf("expected default classification after reset, got %s", strength)
+	}
+}
@@ -0,0 +1,18 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// SecureCompare reports whether the secrets a and b are equal in constant time.
+// Use it instead of == when comparing passwords, tokens or other secrets, so the
+// time taken does not reveal how many leading bytes matched.
+// Both inputs are hashed before comparison, so the running time does not depend
+// on where the inputs differ or on whether their lengths match.
+func SecureCompare(a, b string) bool {
+	ha := sha256.Sum256([]byte(a))
+	hb := sha256.Sum256([]byte(b))
+	sameLength := subtle.ConstantTimeEq(int32(len(a)), int32(len(b)))
+	return subtle.ConstantTimeCompare(ha[:], hb[:])&sameLength == 1
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "testing"
+
+// TestSecureCompare checks that SecureCompare reports equality correctly.
+func TestSecureCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"x8#Kq2!vLm9@", "x8#Kq2!vLm9@", true},
+		{"", "", true},
+		{"x8#Kq2!vLm9@", "x8#Kq2!vLm9#", false},
+		{"secret", "secret!", false},
+		{"secret", "", false},
+	}
+	for _, tt := range tests {
+		if got := SecureCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("SecureCompare(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package generator
+
+import "strings"
+
+// Concatenate joins passwords into a single secret with no delimiters, e.g. to
+// build a long master key from several validated chunks. The chunks are drawn
+// independently, so the entropy of the result is the sum of theirs and the
+// strength is classified from that sum.
+func Concatenate(passwords []GeneratedPassword) GeneratedPassword {
+	var value strings.Builder
+	var entropy float64
+	for _, p := range passwords {
+		value.WriteString(p.Value)
+		entropy += p.Entropy
+	}
+	return GeneratedPassword{
+		Value:    value.String(),
+		Strength: classifyEntropy(entropy),
+		Entropy:  entropy,
+	}
+}
@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestConcatenate checks that the length and entropy of the combined secret are
+// the sums of its parts.
+func TestConcatenate(t *testing.T) {
+	opt := PasswordOptions{Length: 16, UseLower: true, UseNumbers: true, Count: 4}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	combined := Concatenate(passwords)
+
+	var length int
+	var entropy float64
+	for _, p := range passwords {
+		if !strings.Contains(combined.Value, p.Value) {
+			t.Errorf("combined secret %s is missing chunk %s", combined.Value, p.Value)
+		}
+		length += len(p.Value)
+		entropy += p.Entropy
+	}
+	if len(combined.Value) != length {
+		t.Errorf("expected length %d, got %d", length, len(combined.Value))
+	}
+	if want := 64 * math.Log2(36); math.Abs(combined.Entropy-want) > 1e-9 || math.Abs(combined.Entropy-entropy) > 1e-9 {
+		t.Errorf("expected entropy %.2f, got %.2f", want, combined.Entropy)
+	}
+	if combined.Strength != classifyEntropy(combined.Entropy) {
+		t.Errorf("unexpected strength %q", combined.Strength)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// now returns the current time. Tests replace it to pin date tokens.
+var now = time.Now
+
+// ExpandDateToken expands the verbs of a date token format for t:
+//
+//	%Y  four-digit year, e.g. 2024
+//	%y  two-digit year, e.g. 24
+//	%m  two-digit month, 01-12
+//	%d  two-digit day of the month, 01-31
+//	%q  quarter, 1-4
+//	%V  two-digit ISO 8601 week number, 01-53
+//	%%  a literal percent sign
+//
+// Any other character is copied unchanged, so "%YQ%q-" expands to "2024Q1-".
+func ExpandDateToken(format string, t time.Time) (string, error) {
+	var b strings.Builder
+	runes := []rune(format)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			b.WriteRune(runes[i])
+			continue
+		}
+		if i+1 == len(runes) {
+			return "", fmt.Errorf("date token %q ends with a lone %%", format)
+		}
+		i++
+		switch runes[i] {
+		case 'Y':
+			fmt.Fprintf(&b, "%04d", t.Year())
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'q':
+			fmt.Fprintf(&b, "%d", (int(t.Month())-1)/3+1)
+		case 'V':
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", week)
+		case '%':
+			b.WriteByte('%')
+		default:
+			return "", fmt.Errorf("unknown date token verb %%%c in %q", runes[i], format)
+		}
+	}
+	return b.String(), nil
+}
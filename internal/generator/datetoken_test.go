@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExpandDateToken checks every verb against a fixed date and rejects unknown verbs.
+func TestExpandDateToken(t *testing.T) {
+	date := time.Date(2024, time.February, 15, 12, 0, 0, 0, time.UTC)
+	got, err := ExpandDateToken("%YQ%q-%y%m%d-W%V-100%%", date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "2024Q1-240215-W07-100%"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if _, err := ExpandDateToken("%Q", date); err == nil {
+		t.Error("expected error for unknown verb")
+	}
+	if _, err := ExpandDateToken("abc%", date); err == nil {
+		t.Error("expected error for trailing %")
+	}
+}
+
+// TestGeneratePassword_DateToken checks that the token is added around the random
+// part without counting toward Length or entropy.
+func TestGeneratePassword_DateToken(t *testing.T) {
+	orig := now
+	now = func() time.Time { return time.Date(2024, time.November, 3, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = orig })
+
+	opt := PasswordOptions{
+		Length:          12,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           5,
+		DateToken:       "%YQ%q-",
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		random, ok := strings.CutPrefix(gp.Value, "2024Q4-")
+		if !ok {
+			t.Fatalf("expected password to start with the date token, got %s", gp.Value)
+		}
+		if len(random) != 12 {
+			t.Errorf("expected a 12-character random part, got %q", random)
+		}
+		if want := poolEntropy(12, newPools(opt).charset); gp.Entropy != want {
+			t.Errorf("expected entropy %.2f for the random part only, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	opt.DateToken, opt.DateTokenAppend, opt.Prefix = "-%y%m", true, "svc-"
+	passwords, err = GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := passwords[0].Value; !strings.HasPrefix(v, "svc-") || !strings.HasSuffix(v, "-2411") || len(v) != 4+12+5 {
+		t.Errorf("expected svc-<random>-2411, got %s", v)
+	}
+
+	opt.DateToken = "%x"
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for an invalid date token")
+	}
+}
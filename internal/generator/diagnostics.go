@@ -0,0 +1,43 @@
+package generator
+
+import "fmt"
+
+// Diagnostic codes reported in GenerateResult.Diagnostics.
+const (
+	DiagnosticWeakEntropy = "weak-entropy" // Each password has less than weakEntropy bits
+	DiagnosticHighRetries = "high-retries" // Constraints discarded many candidates
+	DiagnosticDuplicates  = "duplicates"   // The batch contains repeated passwords (reported by callers)
+)
+
+// weakEntropy is the entropy in bits below which a run is reported as weak.
+// It matches the upper bound of the default "Weak" label.
+const weakEntropy = 40
+
+// highRetriesPerPassword is the average number of discarded candidates per
+// password above which a run is reported as over-constrained.
+const highRetriesPerPassword = 10
+
+// Diagnostic is a machine-readable warning about a generation run.
+type Diagnostic struct {
+	Code    string `json:"code"`    // Stable identifier, one of the Diagnostic* constants
+	Message string `json:"message"` // Human-readable description
+}
+
+// diagnose returns the diagnostics for a run of opt that produced passwords of
+// the given entropy after the given number of retries.
+func diagnose(opt PasswordOptions, entropy float64, retries int) []Diagnostic {
+	var diagnostics []Diagnostic
+	if entropy < weakEntropy {
+		diagnostics = append(diagnostics, Diagnostic{
+			Code:    DiagnosticWeakEntropy,
+			Message: fmt.Sprintf("each password has only %.2f bits of entropy; use a longer length or more character sets", entropy),
+		})
+	}
+	if retries > highRetriesPerPassword*opt.Count {
+		diagnostics = append(diagnostics, Diagnostic{
+			Code:    DiagnosticHighRetries,
+			Message: fmt.Sprintf("%d candidates were discarded for %d passwords; the constraints may be too strict", retries, opt.Count),
+		})
+	}
+	return diagnostics
+}
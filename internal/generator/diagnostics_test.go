@@ -0,0 +1,33 @@
+package generator
+
+import "testing"
+
+// TestGenerateWithResult_Diagnostics checks that weak and over-constrained runs
+// are diagnosed and that a sound run is not.
+func TestGenerateWithResult_Diagnostics(t *testing.T) {
+	result, err := GenerateWithResult(PasswordOptions{Length: 6, UseNumbers: true, Count: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Code != DiagnosticWeakEntropy {
+		t.Errorf("expected a weak entropy diagnostic, got %+v", result.Diagnostics)
+	}
+
+	// Only about 1.3% of 14-character lowercase candidates have no repeated
+	// letter, so about 75 candidates are discarded per password.
+	result, err = GenerateWithResult(PasswordOptions{Length: 14, UseLower: true, Count: 20, MinDistinct: 14})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Code != DiagnosticHighRetries {
+		t.Errorf("expected a high retries diagnostic, got %+v", result.Diagnostics)
+	}
+
+	result, err = GenerateWithResult(PasswordOptions{Length: 16, UseLower: true, UseNumbers: true, Count: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Diagnostics != nil {
+		t.Errorf("expected no diagnostics, got %+v", result.Diagnostics)
+	}
+}
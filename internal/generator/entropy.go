@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// EntropyMethod selects the formula used to estimate the entropy of a password.
+type EntropyMethod int
+
+const (
+	// CharsetBased estimates entropy as length * log2(charsetSize), where the
+	// charset is inferred from the character classes present in the password.
+	// It measures the search space of a brute-force attack over those classes
+	// and ignores how the characters are actually distributed.
+	CharsetBased EntropyMethod = iota
+
+	// Shannon estimates entropy from the observed character frequencies:
+	// length * -sum(p * log2(p)). It rewards variety and penalizes repetition,
+	// so "aaaaaaaa" scores 0 bits while CharsetBased would report ~37.6 bits.
+	// It is useful when auditing existing passwords, but it never exceeds
+	// length * log2(length) and therefore understates short random passwords.
+	Shannon
+)
+
+// String returns the name of the entropy method.
+func (m EntropyMethod) String() string {
+	switch m {
+	case CharsetBased:
+		return "charset"
+	case Shannon:
+		return "shannon"
+	default:
+		return fmt.Sprintf("EntropyMethod(%d)", int(m))
+	}
+}
+
+// PasswordEntropyWithMethod calculates the entropy of a password using the
+// given method and returns (entropy, strength label, error).
+// The strength bands are the same as for PasswordEntropy.
+func PasswordEntropyWithMethod(password string, method EntropyMethod) (float64, string, error) {
+	switch method {
+	case CharsetBased:
+		return PasswordEntropy(password)
+	case Shannon:
+		if len(password) == 0 {
+			return 0, "", errors.New("password is empty")
+		}
+		entropy := shannonEntropy(password)
+		return entropy, classifyEntropy(entropy), nil
+	default:
+		return 0, "", fmt.Errorf("unknown entropy method: %d", int(method))
+	}
+}
+
+// shannonEntropy returns the total Shannon entropy in bits of the password,
+// computed over the observed rune frequencies.
+func shannonEntropy(password string) float64 {
+	runes := []rune(password)
+	freq := make(map[rune]int)
+	for _, r := range runes {
+		freq[r]++
+	}
+
+	n := float64(len(runes))
+	var perChar float64
+	for _, c := range freq {
+		p := float64(c) / n
+		perChar -= p * math.Log2(p)
+	}
+	return perChar * n
+}
+
+// MaxEntropy returns the entropy in bits of a password generated with opt,
+// computed as Length * log2(charsetSize) over the enabled character sets and
+// any extra characters.
+// It describes the configuration rather than a concrete password, so no
+// password is generated. Count is ignored.
+func MaxEntropy(opt PasswordOptions) (float64, error) {
+	opt.Count = max(opt.Count, 1)
+	opt, err := resolveOptions(opt)
+	if err != nil {
+		return 0, err
+	}
+	return poolEntropy(opt.Length, newPools(opt).charset), nil
+}
+
+// Keyspace returns the number of distinct passwords opt can describe, the
+// charset size raised to the power of Length, before constraints such as the
+// per-class guarantees remove some of them. Its base-2 logarithm is
+// MaxEntropy. Count is ignored.
+func Keyspace(opt PasswordOptions) (*big.Int, error) {
+	opt.Count = max(opt.Count, 1)
+	opt, err := resolveOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+	size := big.NewInt(int64(len(newPools(opt).charset)))
+	return size.Exp(size, big.NewInt(int64(opt.Length)), nil), nil
+}
+
+// TrueEntropy returns the entropy in bits of a password generated with opt,
+// accounting for the guaranteed characters. Forcing one character from each
+// required set rules out every password that lacks one, such as an all-letter
+// password when digits are enabled, so the real keyspace is the number of
+// strings of Length characters over the pool that contain a character of each
+// required set (or of at least opt.MinClasses sets). The shuffle spreads the
+// guaranteed characters over every position, so the result is the base-2
+// logarithm of that count, computed exactly by inclusion-exclusion over the
+// sets. It is below MaxEntropy, noticeably so for short passwords. Other
+// constraints, such as maxima and edge rules, reduce the keyspace further and
+// are not accounted for. Count is ignored.
+func TrueEntropy(opt PasswordOptions) (float64, error) {
+	opt.Count = max(opt.Count, 1)
+	opt, err := resolveOptions(opt)
+	if err != nil {
+		return 0, err
+	}
+	classes := enabledClasses(opt)
+	extra := len(newPools(opt).charset)
+	for _, c := range classes {
+		extra -= len(c.chars)
+	}
+	required := len(classes)
+	if opt.MinClasses > 0 {
+		required = opt.MinClasses
+	}
+
+	// only[s] counts the strings drawn from the classes in subset s (and the
+	// extra characters); exactly[s] those in which every class of s appears.
+	subsets := 1 << len(classes)
+	only := make([]*big.Int, subsets)
+	for s := range subsets {
+		size := extra
+		for i, c := range classes {
+			if s&(1<<i) != 0 {
+				size += len(c.chars)
+			}
+		}
+		only[s] = new(big.Int).Exp(big.NewInt(int64(size)), big.NewInt(int64(opt.Length)), nil)
+	}
+	valid := new(big.Int)
+	for s := range subsets {
+		if bits.OnesCount(uint(s)) < required {
+			continue
+		}
+		// Inclusion-exclusion over the subsets t of s.
+		exactly := new(big.Int)
+		for t := s; ; t = (t - 1) & s {
+			if (bits.OnesCount(uint(s))-bits.OnesCount(uint(t)))%2 == 0 {
+				exactly.Add(exactly, only[t])
+			} else {
+				exactly.Sub(exactly, only[t])
+			}
+			if t == 0 {
+				break
+			}
+		}
+		valid.Add(valid, exactly)
+	}
+	return log2Int(valid), nil
+}
+
+// log2Int returns the base-2 logarithm of a positive n.
+func log2Int(n *big.Int) float64 {
+	// Keep the top 64 bits so that the conversion to float64 is exact enough.
+	shift := max(n.BitLen()-64, 0)
+	top := new(big.Int).Rsh(n, uint(shift))
+	f, _ := new(big.Float).SetInt(top).Float64()
+	return math.Log2(f) + float64(shift)
+}
+
+// SuggestLength returns the shortest Length for which a password generated
+// with opt reaches at least bits of entropy (see MaxEntropy), never below the
+// length the options need to be valid, such as one character per required set
+// or a policy's MinLength. Enabling more character sets, up to every set with
+// FullASCIISymbols, yields the most entropy per character and so the shortest
+// length. opt.Length and opt.Count are ignored. It returns an error if the
+// options are invalid or would need more than 1024 characters.
+func SuggestLength(bits float64, opt PasswordOptions) (int, error) {
+	if bits <= 0 || math.IsInf(bits, 0) || math.IsNaN(bits) {
+		return 0, errors.New("target entropy must be a positive number of bits")
+	}
+	opt.Count = 1
+	opt.Length = 0
+	widened, err := applyPolicies(opt)
+	if err != nil {
+		return 0, err
+	}
+	size := len(newPools(widened).charset)
+	if size < 2 {
+		return 0, errors.New("at least two characters are needed to reach an entropy target")
+	}
+	length := max(int(math.Ceil(bits/math.Log2(float64(size)))), widened.Length)
+	if length > maxBudgetLength {
+		return 0, fmt.Errorf("target entropy needs %d characters, more than the maximum of %d", length, maxBudgetLength)
+	}
+	// Constraints such as the per-set guarantees may need a longer password.
+	for ; length <= maxBudgetLength; length++ {
+		opt.Length = length
+		if _, err = resolveOptions(opt); err == nil {
+			return length, nil
+		}
+	}
+	return 0, err
+}
+
+// maxBudgetLength caps the per-password length GenerateBudget may choose.
+const maxBudgetLength = 1024
+
+// GenerateBudget generates count passwords whose combined entropy is at least
+// totalBits, for example to split a budget across the levels of a key
+// hierarchy. Every password gets the same length: the shortest valid one, as
+// chosen by SuggestLength, for which count passwords reach the budget.
+// opt.Length and opt.Count are ignored.
+// It returns an error if the budget would need passwords longer than 1024
+// characters.
+func GenerateBudget(totalBits float64, count int, opt PasswordOptions) ([]GeneratedPassword, error) {
+	if totalBits <= 0 || math.IsInf(totalBits, 0) || math.IsNaN(totalBits) {
+		return nil, errors.New("entropy budget must be a positive number of bits")
+	}
+	if count < 1 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	// Each password needs an equal share of the budget; SuggestLength widens the
+	// options with their policies and lengthens them until they are valid.
+	length, err := SuggestLength(totalBits/float64(count), opt)
+	if err != nil {
+		return nil, fmt.Errorf("entropy budget of %g bits over %d passwords: %w", totalBits, count, err)
+	}
+	opt.Length, opt.Count = length, count
+	return GeneratePassword(opt)
+}
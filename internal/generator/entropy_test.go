@@ -0,0 +1,253 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestPasswordEntropyWithMethod_Repetitive checks that Shannon entropy is far lower
+// than charset-based entropy for a highly repetitive password.
+func TestPasswordEntropyWithMethod_Repetitive(t *testing.T) {
+	pwd := "aaaaaaaaaaaaaaab"
+
+	charset, _, err := PasswordEntropyWithMethod(pwd, CharsetBased)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shannon, strength, err := PasswordEntropyWithMethod(pwd, Shannon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 16 * log2(26) ~= 75.2 bits vs. 16 * H(15/16, 1/16) ~= 5.4 bits
+	if math.Abs(charset-16*math.Log2(26)) > 1e-9 {
+		t.Errorf("unexpected charset-based entropy: %.4f", charset)
+	}
+	if shannon > 6 {
+		t.Errorf("expected Shannon entropy below 6 bits, got %.4f", shannon)
+	}
+	if charset-shannon < 60 {
+		t.Errorf("expected methods to diverge significantly, got charset=%.2f shannon=%.2f", charset, shannon)
+	}
+	if strength != "Weak" {
+		t.Errorf("expected Weak strength for Shannon method, got %s", strength)
+	}
+}
+
+// TestPasswordEntropyWithMethod_Distinct checks that Shannon entropy equals
+// length * log2(length) when every character is distinct.
+func TestPasswordEntropyWithMethod_Distinct(t *testing.T) {
+	pwd := "aB3$xY7!"
+	shannon, _, err := PasswordEntropyWithMethod(pwd, Shannon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 8 * math.Log2(8); math.Abs(shannon-want) > 1e-9 {
+		t.Errorf("expected %.4f bits, got %.4f", want, shannon)
+	}
+}
+
+// TestPasswordEntropyWithMethod_Invalid checks that empty passwords and unknown methods return an error.
+func TestPasswordEntropyWithMethod_Invalid(t *testing.T) {
+	if _, _, err := PasswordEntropyWithMethod("", Shannon); err == nil {
+		t.Error("expected error for empty password")
+	}
+	if _, _, err := PasswordEntropyWithMethod("abc", EntropyMethod(42)); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+// TestMaxEntropy checks the entropy computed for a configuration.
+func TestMaxEntropy(t *testing.T) {
+	opt := PasswordOptions{Length: 12, UseUpper: true, UseLower: true, UseNumbers: true, UseSpecialChars: true}
+	got, err := MaxEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 12 * math.Log2(89); math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected %.4f bits, got %.4f", want, got)
+	}
+
+	if _, err := MaxEntropy(PasswordOptions{Length: 12}); err == nil {
+		t.Error("expected error when no character set is selected")
+	}
+}
+
+// TestKeyspace checks that the keyspace is the charset size to the power of the
+// length and agrees with MaxEntropy.
+func TestKeyspace(t *testing.T) {
+	opt := PasswordOptions{Length: 4, UseNumbers: true}
+	got, err := Keyspace(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Int64() != 10000 {
+		t.Errorf("expected 10000, got %s", got)
+	}
+
+	opt = PasswordOptions{Length: 20, UseUpper: true, UseLower: true, UseNumbers: true, UseSpecialChars: true}
+	space, err := Keyspace(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entropy, err := MaxEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bits := float64(space.BitLen()); math.Abs(bits-entropy) > 1 {
+		t.Errorf("expected a keyspace of about 2^%.2f, got 2^%.0f", entropy, bits)
+	}
+
+	if _, err := Keyspace(PasswordOptions{Length: 12}); err == nil {
+		t.Error("expected error when no character set is selected")
+	}
+}
+
+// TestTrueEntropy checks that TrueEntropy counts only the passwords containing
+// every required set, and approaches MaxEntropy for long passwords.
+func TestTrueEntropy(t *testing.T) {
+	// Two characters with a digit and an uppercase letter: 2*10*26 passwords
+	// rather than the naive 36^2.
+	opt := PasswordOptions{Length: 2, UseNumbers: true, UseUpper: true}
+	got, err := TrueEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := math.Log2(520); math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected %.4f bits, got %.4f", want, got)
+	}
+	naive, err := MaxEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got >= naive {
+		t.Errorf("expected less than the naive %.4f bits, got %.4f", naive, got)
+	}
+
+	// With MinClasses 1 every string over the pool qualifies.
+	opt.MinClasses = 1
+	if got, err := TrueEntropy(opt); err != nil || math.Abs(got-naive) > 1e-9 {
+		t.Errorf("expected %.4f bits with one required class, got %.4f (%v)", naive, got, err)
+	}
+
+	opt = PasswordOptions{Length: 64, UseUpper: true, UseLower: true, UseNumbers: true, UseSpecialChars: true}
+	got, err = TrueEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	naive, err = MaxEntropy(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got >= naive || naive-got > 0.01 {
+		t.Errorf("expected slightly less than %.4f bits for a long password, got %.4f", naive, got)
+	}
+
+	if _, err := TrueEntropy(PasswordOptions{Length: 12}); err == nil {
+		t.Error("expected error when no character set is selected")
+	}
+}
+
+// TestSuggestLength checks that the suggested length reaches the target with no
+// character to spare, and that the maximal charset needs fewer characters than
+// an alphanumeric one.
+func TestSuggestLength(t *testing.T) {
+	alnum := PasswordOptions{UseUpper: true, UseLower: true, UseNumbers: true}
+	maximal := PasswordOptions{UseUpper: true, UseLower: true, UseNumbers: true, UseSpecialChars: true, FullASCIISymbols: true}
+	short, err := SuggestLength(128, maximal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	long, err := SuggestLength(128, alnum)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if short != 20 || long != 22 {
+		t.Errorf("expected 20 characters with every set and 22 alphanumeric, got %d and %d", short, long)
+	}
+	for _, length := range []int{short, short - 1} {
+		opt := maximal
+		opt.Length = length
+		entropy, err := MaxEntropy(opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if (entropy >= 128) != (length == short) {
+			t.Errorf("unexpected %.2f bits at length %d", entropy, length)
+		}
+	}
+
+	// The per-set guarantees need four characters even for a tiny target.
+	if got, err := SuggestLength(1, maximal); err != nil || got != 4 {
+		t.Errorf("expected 4 characters for a 1-bit target, got %d (%v)", got, err)
+	}
+	if _, err := SuggestLength(1e6, alnum); err == nil {
+		t.Error("expected error for a target needing overly long passwords")
+	}
+	if _, err := SuggestLength(0, alnum); err == nil {
+		t.Error("expected error for a zero target")
+	}
+}
+
+// TestGenerateBudget checks that the summed entropy meets the budget with the
+// shortest possible length, and that unreachable budgets are rejected.
+func TestGenerateBudget(t *testing.T) {
+	opt := PasswordOptions{UseLower: true, UseNumbers: true}
+	passwords, err := GenerateBudget(256, 3, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(passwords) != 3 {
+		t.Fatalf("expected 3 passwords, got %d", len(passwords))
+	}
+	var total float64
+	for _, gp := range passwords {
+		total += gp.Entropy
+	}
+	if total < 256 {
+		t.Errorf("expected at least 256 bits in total, got %.2f", total)
+	}
+	// One character less per password would fall short of the budget.
+	shorter := 3 * float64(len(passwords[0].Value)-1) * math.Log2(36)
+	if shorter >= 256 {
+		t.Errorf("expected the shortest sufficient length, got %d characters", len(passwords[0].Value))
+	}
+
+	// Policies, required categories and groups may need longer passwords than
+	// the budget alone.
+	constrained := PasswordOptions{
+		UseLower:          true,
+		ExtraChars:        "ÄÖÜ",
+		RequireCategory:   []string{"Lu"},
+		RequireFromGroups: [][]rune{[]rune("abc"), []rune("def"), []rune("ghi"), []rune("jkl")},
+		Policies:          []Policy{{Name: "long", MinLength: 16, RequireNumbers: true}},
+	}
+	passwords, err = GenerateBudget(8, 2, constrained)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if n := len([]rune(gp.Value)); n != 16 {
+			t.Errorf("expected the policy's 16 characters, got %d in %q", n, gp.Value)
+		}
+	}
+	constrained.Policies = nil
+	passwords, err = GenerateBudget(8, 2, constrained)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len([]rune(passwords[0].Value)); n != 6 {
+		t.Errorf("expected 6 characters for the required category, set and groups, got %d", n)
+	}
+
+	if _, err := GenerateBudget(1e6, 1, opt); err == nil || !strings.Contains(err.Error(), "entropy budget") {
+		t.Errorf("expected an entropy budget error for overly long passwords, got %v", err)
+	}
+	if _, err := GenerateBudget(-1, 1, opt); err == nil {
+		t.Error("expected error for a negative budget")
+	}
+	if _, err := GenerateBudget(64, 1, PasswordOptions{}); err == nil {
+		t.Error("expected error without character sets")
+	}
+}
@@ -0,0 +1,18 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// fingerprintLength is the number of hex characters in a fingerprint.
+const fingerprintLength = 8
+
+// Fingerprint returns the first eight hex characters of the SHA-256 of password.
+// It lets two people confirm a password was transmitted correctly without
+// revealing it. A fingerprint is far too short to protect the password from
+// guessing, so treat it as a typo check, never as a hash for storage.
+func Fingerprint(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
@@ -0,0 +1,21 @@
+package generator
+
+import "testing"
+
+// TestFingerprint checks that fingerprints are deterministic, eight hex characters
+// long and differ for different passwords.
+func TestFingerprint(t *testing.T) {
+	// SHA-256("hunter2") = f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7
+	if got := Fingerprint("hunter2"); got != "f52fbd32" {
+		t.Errorf("expected f52fbd32, got %s", got)
+	}
+	if Fingerprint("x8#Kq2!vLm9@") != Fingerprint("x8#Kq2!vLm9@") {
+		t.Error("expected fingerprints to be deterministic")
+	}
+	if got := Fingerprint(""); len(got) != 8 {
+		t.Errorf("expected 8 characters, got %q", got)
+	}
+	if Fingerprint("a") == Fingerprint("b") {
+		t.Error("expected different fingerprints for different passwords")
+	}
+}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+)
+
+// FormatGrouped splits s into groups of size runes joined by sep, e.g.
+// FormatGrouped("abcdefgh", 4, "-") returns "abcd-efgh". The last group may be
+// shorter. It is meant for display only: the separators are not part of the
+// generated randomness. For separators stored as part of the password, see
+// PasswordOptions.RealSeparator. A size below 1 returns s unchanged.
+func FormatGrouped(s string, size int, sep string) string {
+	runes := []rune(s)
+	if size < 1 || len(runes) <= size {
+		return s
+	}
+	groups := make([]string, 0, (len(runes)+size-1)/size)
+	for i := 0; i < len(runes); i += size {
+		groups = append(groups, string(runes[i:min(i+size, len(runes))]))
+	}
+	return strings.Join(groups, sep)
+}
+
+// ReverseRunes returns s with its runes in reverse order, so multi-byte runes
+// such as "é" or emoji stay intact. Combining sequences are reversed rune by
+// rune like any other text.
+func ReverseRunes(s string) string {
+	runes := []rune(s)
+	slices.Reverse(runes)
+	return string(runes)
+}
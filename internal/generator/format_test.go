@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// TestFormatGrouped checks grouping of full and partial groups.
+func TestFormatGrouped(t *testing.T) {
+	tests := []struct {
+		s    string
+		size int
+		sep  string
+		want string
+	}{
+		{"abcdefghijklmnop", 4, "-", "abcd-efgh-ijkl-mnop"},
+		{"abcdefghij", 4, " ", "abcd efgh ij"},
+		{"abc", 4, "-", "abc"},
+		{"abcdef", 0, "-", "abcdef"},
+		{"äöüß", 2, "-", "äö-üß"},
+	}
+	for _, tt := range tests {
+		if got := FormatGrouped(tt.s, tt.size, tt.sep); got != tt.want {
+			t.Errorf("FormatGrouped(%q, %d, %q) = %q, want %q", tt.s, tt.size, tt.sep, got, tt.want)
+		}
+	}
+}
+
+// TestReverseRunes checks that reversal keeps multi-byte runes intact.
+func TestReverseRunes(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"abc123!", "!321cba"},
+		{"", ""},
+		{"pä$s🔑wörd", "dröw🔑s$äp"},
+	}
+	for _, tt := range tests {
+		got := ReverseRunes(tt.s)
+		if got != tt.want {
+			t.Errorf("ReverseRunes(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("ReverseRunes(%q) returned invalid UTF-8", tt.s)
+		}
+	}
+}
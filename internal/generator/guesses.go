@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// Parameters of the guess model used by EstimateGuesses.
+const (
+	bruteforceCardinality = 10  // Guesses per character not covered by a pattern
+	minPatternLength      = 3   // Shortest dictionary word, sequence, repeat or keyboard walk
+	minMatchGuesses       = 10  // Floor on the guesses for any single pattern
+	maxGuessRunes         = 100 // Runes analyzed for patterns; the rest count as bruteforce
+	keyboardKeys          = 47  // Keys on the US QWERTY layout a walk may start from
+	keyboardDegree        = 4   // Average number of neighbours of a key, per turn of a walk
+)
+
+// commonPasswords lists some of the most common leaked passwords, most common
+// first; the position of a password is its guess rank.
+var commonPasswords = strings.Fields(`
+	123456 password 123456789 12345678 12345 qwerty 1234567 111111 1234567890
+	123123 abc123 1234 password1 iloveyou 1q2w3e4r 000000 qwerty123 zaq12wsx
+	dragon sunshine princess letmein 654321 monkey 1qaz2wsx 123321 qwertyuiop
+	superman asdfghjkl football baseball welcome admin login master hello
+	freedom whatever trustno1 shadow michael jennifer charlie passw0rd starwars
+	ninja mustang access flower loveme batman secret summer winter hunter killer
+	pepper ginger cookie chocolate computer soccer jordan michelle daniel
+	thomas tigger robert buster hockey ranger george harley andrew joshua
+	`)
+
+// guessDictionary maps lowercase dictionary words to their guess rank: common
+// passwords by popularity, then the BIP-39 English words, which have no
+// frequency order and all share the rank of the list size.
+var guessDictionary = func() map[string]int {
+	dict := make(map[string]int, len(commonPasswords)+len(wordlists.English))
+	for _, w := range wordlists.English {
+		dict[w] = len(commonPasswords) + len(wordlists.English)
+	}
+	for i, w := range commonPasswords {
+		dict[w] = i + 1
+	}
+	return dict
+}()
+
+// maxDictionaryWord is the length in runes of the longest dictionary word.
+var maxDictionaryWord = func() int {
+	longest := 0
+	for w := range guessDictionary {
+		longest = max(longest, len([]rune(w)))
+	}
+	return longest
+}()
+
+// leetSubstitutions maps common character substitutions back to letters.
+var leetSubstitutions = strings.NewReplacer(
+	"4", "a", "@", "a", "3", "e", "1", "i", "!", "i",
+	"0", "o", "$", "s", "5", "s", "7", "t",
+)
+
+// guessMatch is a pattern covering the runes [start, end) of a password.
+type guessMatch struct {
+	start, end int
+	guesses    float64
+}
+
+// EstimateGuesses estimates how many guesses an attacker needs to find
+// password, using a simplified version of the zxcvbn model. The password is
+// split into the cheapest sequence of patterns: dictionary words (common
+// passwords and English words, reversed, capitalized or with leet
+// substitutions such as "p@ssw0rd"), ascending or descending sequences,
+// repeated characters or blocks, and QWERTY keyboard walks. Every character
+// not covered by a pattern costs bruteforceCardinality guesses.
+//
+// Unlike PasswordEntropy, which credits "Password1" with the full strength of
+// its character sets, it reflects how human-chosen passwords are cracked. Use
+// GuessScore to map the result to a 0-4 score. An empty password needs 0
+// guesses.
+func EstimateGuesses(password string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+	extra := max(len(runes)-maxGuessRunes, 0)
+	runes = runes[:len(runes)-extra]
+
+	matches := slices.Concat(dictionaryMatches(runes), sequenceMatches(runes), repeatMatches(runes), keyboardMatches(runes))
+	// best[k] is the fewest guesses needed for runes[:k].
+	best := make([]float64, len(runes)+1)
+	best[0] = 1
+	for k := 1; k <= len(runes); k++ {
+		best[k] = best[k-1] * bruteforceCardinality
+		for _, m := range matches {
+			if m.end == k {
+				best[k] = min(best[k], best[m.start]*max(m.guesses, minMatchGuesses))
+			}
+		}
+	}
+	return min(best[len(runes)]*math.Pow(bruteforceCardinality, float64(extra)), math.MaxFloat64)
+}
+
+// GuessScore maps a guess count from EstimateGuesses to a score from 0 (too
+// guessable) to 4 (very unguessable), using the zxcvbn thresholds of 10^3,
+// 10^6, 10^8 and 10^10 guesses.
+func GuessScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// dictionaryMatches returns every substring of runes that is a dictionary
+// word, possibly reversed, capitalized or with leet substitutions.
+func dictionaryMatches(runes []rune) []guessMatch {
+	var matches []guessMatch
+	for i := range runes {
+		for j := i + minPatternLength; j <= min(len(runes), i+maxDictionaryWord); j++ {
+			word := strings.ToLower(string(runes[i:j]))
+			variations := caseVariations(runes[i:j])
+			if _, ok := guessDictionary[word]; !ok {
+				if unleeted := leetSubstitutions.Replace(word); unleeted != word {
+					word = unleeted
+					variations *= 2
+				}
+			}
+			if rank, ok := guessDictionary[word]; ok {
+				matches = append(matches, guessMatch{i, j, float64(rank) * variations})
+			} else if rank, ok := guessDictionary[ReverseRunes(word)]; ok {
+				matches = append(matches, guessMatch{i, j, float64(rank) * variations * 2})
+			}
+		}
+	}
+	return matches
+}
+
+// caseVariations returns the number of capitalizations an attacker tries for
+// a word with the letter case of runes: 1 for all lowercase, 2 for a capital
+// first letter or all capitals, and 2 per uppercase letter beyond that.
+func caseVariations(runes []rune) float64 {
+	var upper, lower int
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0 || (upper == 1 && unicode.IsUpper(runes[0])):
+		return 2
+	default:
+		return math.Pow(2, float64(min(upper, lower)))
+	}
+}
+
+// sequenceMatches returns every maximal ascending or descending run of at
+// least minPatternLength characters within A-Z, a-z or 0-9, such as "abc" or
+// "987". A run starting at either end of its set is the cheapest to
+// guess, then digits, then letters.
+func sequenceMatches(runes []rune) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i < len(runes)-1; {
+		set, pos := orderedPos(runes[i])
+		_, next := orderedPos(runes[i+1])
+		step := next - pos
+		j := i + 1
+		for set >= 0 && (step == 1 || step == -1) && j < len(runes) {
+			s, p := orderedPos(runes[j])
+			if s != set || p-pos != step*(j-i) {
+				break
+			}
+			j++
+		}
+		if j-i < minPatternLength {
+			i++
+			continue
+		}
+		chars := orderedSets[set]
+		base := 26.0
+		switch {
+		case pos == 0 || pos == len(chars)-1:
+			base = 4
+		case chars == numbers:
+			base = 10
+		}
+		if step < 0 {
+			base *= 2
+		}
+		matches = append(matches, guessMatch{i, j, base * float64(j-i)})
+		i = j - 1
+	}
+	return matches
+}
+
+// repeatMatches returns every run of at least minPatternLength copies of a
+// character, and every block of two or more characters repeated back to back,
+// such as "abcabc", using the shortest repeating unit at each position. A
+// repeat costs the guesses of its unit times the number of copies.
+func repeatMatches(runes []rune) []guessMatch {
+	var matches []guessMatch
+	for i := range runes {
+		for size := 1; i+2*size <= len(runes); size++ {
+			unit := runes[i : i+size]
+			copies := 1
+			for end := i + size; end+size <= len(runes) && slices.Equal(runes[end:end+size], unit); end += size {
+				copies++
+			}
+			if copies < 2 || size*copies < minPatternLength {
+				continue
+			}
+			guesses := float64(charClassSize(unit[0]))
+			if size > 1 {
+				guesses = EstimateGuesses(string(unit))
+			}
+			matches = append(matches, guessMatch{i, i + size*copies, guesses * float64(copies)})
+			// Longer units starting here only cover fewer copies of this one.
+			break
+		}
+	}
+	return matches
+}
+
+// charClassSize returns the size of the character set r belongs to.
+func charClassSize(r rune) int {
+	switch {
+	case strings.ContainsRune(numbers, r):
+		return len(numbers)
+	case strings.ContainsRune(lowercase, r):
+		return len(lowercase)
+	case strings.ContainsRune(uppercase, r):
+		return len(uppercase)
+	default:
+		return len(specialChars)
+	}
+}
+
+// keyboardMatches returns every maximal walk of at least minPatternLength
+// characters over neighbouring QWERTY keys, such as "qwerty" or "zaq1". A walk
+// costs more the more often it changes direction, and twice as much if it uses
+// Shift.
+func keyboardMatches(runes []rune) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && keyboardAdjacent(runes[j-1], runes[j]) {
+			j++
+		}
+		if j-i < minPatternLength {
+			i = j
+			continue
+		}
+		turns := 1
+		var shifted bool
+		for k := i; k < j; k++ {
+			if needsShift(runes[k]) {
+				shifted = true
+			}
+			if k >= i+2 && keyDirection(runes[k-2], runes[k-1]) != keyDirection(runes[k-1], runes[k]) {
+				turns++
+			}
+		}
+		guesses := keyboardKeys * float64(j-i) * math.Pow(keyboardDegree, float64(turns))
+		if shifted {
+			guesses *= 2
+		}
+		matches = append(matches, guessMatch{i, j, guesses})
+		i = j
+	}
+	return matches
+}
+
+// keyDirection returns the row and column offsets from key a to key b.
+func keyDirection(a, b rune) keyPos {
+	pa, pb := qwertyPositions[a], qwertyPositions[b]
+	return keyPos{pb.row - pa.row, pb.col - pa.col}
+}
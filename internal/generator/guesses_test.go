@@ -0,0 +1,43 @@
+package generator
+
+import "testing"
+
+// TestEstimateGuesses checks that common patterns need far fewer guesses than
+// a random string of similar length, and their scores.
+func TestEstimateGuesses(t *testing.T) {
+	tests := []struct {
+		password string
+		score    int
+	}{
+		{"password1", 0},
+		{"P@ssw0rd", 0},
+		{"drowssap", 0},
+		{"qwerty", 0},
+		{"abcdef", 0},
+		{"aaaaaaaa", 0},
+		{"abcabcabc", 0},
+		{"Xk9#vQ2m!Lp7zR4w", 4},
+	}
+	for _, tt := range tests {
+		if got := GuessScore(EstimateGuesses(tt.password)); got != tt.score {
+			t.Errorf("GuessScore(EstimateGuesses(%q)) = %d, want %d", tt.password, got, tt.score)
+		}
+	}
+
+	weak, strong := EstimateGuesses("password1"), EstimateGuesses("Xk9#vQ2m!")
+	if weak*1e6 > strong {
+		t.Errorf("expected password1 (%g guesses) to be far weaker than a random string (%g)", weak, strong)
+	}
+	if got := EstimateGuesses(""); got != 0 {
+		t.Errorf("expected 0 guesses for an empty password, got %g", got)
+	}
+}
+
+// TestGuessScore checks the score thresholds.
+func TestGuessScore(t *testing.T) {
+	for guesses, want := range map[float64]int{1: 0, 1e3: 0, 1e4: 1, 1e7: 2, 1e9: 3, 1e11: 4} {
+		if got := GuessScore(guesses); got != want {
+			t.Errorf("GuessScore(%g) = %d, want %d", guesses, got, want)
+		}
+	}
+}
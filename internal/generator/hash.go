@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashAlgo identifies a password hashing algorithm.
+type HashAlgo string
+
+const (
+	HashBcrypt   HashAlgo = "bcrypt"   // bcrypt, encoded in the standard $2a$ format
+	HashArgon2id HashAlgo = "argon2id" // Argon2id, encoded in the PHC string format
+)
+
+// Default Argon2id parameters, following the second recommended option of RFC 9106.
+const (
+	defaultArgon2Time       = 3
+	defaultArgon2Memory     = 64 * 1024 // KiB
+	defaultArgon2Threads    = 4
+	defaultArgon2SaltLength = 16
+	defaultArgon2KeyLength  = 32
+)
+
+// HashParams tunes the cost of password hashing.
+// Zero values select sensible defaults for the chosen algorithm.
+type HashParams struct {
+	Cost       int    // bcrypt cost factor
+	Time       uint32 // Argon2id number of passes
+	Memory     uint32 // Argon2id memory in KiB
+	Threads    uint8  // Argon2id degree of parallelism
+	SaltLength uint32 // Argon2id salt length in bytes
+	KeyLength  uint32 // Argon2id derived key length in bytes
+}
+
+// withDefaults returns a copy of p with zero values replaced by defaults.
+func (p HashParams) withDefaults() HashParams {
+	if p.Cost == 0 {
+		p.Cost = bcrypt.DefaultCost
+	}
+	if p.Time == 0 {
+		p.Time = defaultArgon2Time
+	}
+	if p.Memory == 0 {
+		p.Memory = defaultArgon2Memory
+	}
+	if p.Threads == 0 {
+		p.Threads = defaultArgon2Threads
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = defaultArgon2SaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = defaultArgon2KeyLength
+	}
+	return p
+}
+
+// ParseHashAlgo converts a name such as "bcrypt" or "argon2id" into a HashAlgo.
+func ParseHashAlgo(name string) (HashAlgo, error) {
+	switch algo := HashAlgo(name); algo {
+	case HashBcrypt, HashArgon2id:
+		return algo, nil
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm %q (expected bcrypt or argon2id)", name)
+	}
+}
+
+// HashPassword hashes the password with the given algorithm and returns the
+// encoded hash, which embeds the salt and cost parameters.
+// A fresh random salt is generated for every call.
+func HashPassword(password string, algo HashAlgo, params HashParams) (string, error) {
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	params = params.withDefaults()
+
+	switch algo {
+	case HashBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), params.Cost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password with bcrypt: %w", err)
+		}
+		return string(hash), nil
+	case HashArgon2id:
+		return HashPHC(password, params)
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm %q", algo)
+	}
+}
+
+// PHC holds the components of an Argon2id hash in the PHC string format.
+type PHC struct {
+	Params HashParams // Cost parameters; SaltLength and KeyLength match Salt and Key
+	Salt   []byte     // Per-password random salt
+	Key    []byte     // Derived key
+}
+
+// String encodes h in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>.
+func (h PHC) String() string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Params.Memory, h.Params.Time, h.Params.Threads,
+		base64.RawStdEncoding.EncodeToString(h.Salt),
+		base64.RawStdEncoding.EncodeToString(h.Key))
+}
+
+// HashPHC hashes the password with Argon2id under a fresh random salt and
+// returns the PHC string, which embeds the salt and cost parameters.
+func HashPHC(password string, params HashParams) (string, error) {
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	params = params.withDefaults()
+
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLength)
+	return PHC{Params: params, Salt: salt, Key: key}.String(), nil
+}
+
+// ParsePHC decodes an Argon2id PHC string produced by HashPHC.
+func ParsePHC(encoded string) (PHC, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != string(HashArgon2id) {
+		return PHC{}, errors.New("not an argon2id PHC string")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return PHC{}, fmt.Errorf("invalid PHC version %q: %w", parts[2], err)
+	}
+	if version != argon2.Version {
+		return PHC{}, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	var h PHC
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.Params.Memory, &h.Params.Time, &h.Params.Threads); err != nil {
+		return PHC{}, fmt.Errorf("invalid PHC parameters %q: %w", parts[3], err)
+	}
+	if h.Params.Memory == 0 || h.Params.Time == 0 || h.Params.Threads == 0 {
+		return PHC{}, fmt.Errorf("invalid PHC parameters %q", parts[3])
+	}
+
+	var err error
+	if h.Salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return PHC{}, fmt.Errorf("invalid PHC salt: %w", err)
+	}
+	if h.Key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return PHC{}, fmt.Errorf("invalid PHC key: %w", err)
+	}
+	if len(h.Salt) == 0 || len(h.Key) == 0 {
+		return PHC{}, errors.New("PHC salt and key must not be empty")
+	}
+	h.Params.SaltLength = uint32(len(h.Salt))
+	h.Params.KeyLength = uint32(len(h.Key))
+	return h, nil
+}
+
+// VerifyPassword reports whether password matches an encoded hash produced by
+// HashPassword. Both bcrypt and Argon2id PHC strings are accepted.
+func VerifyPassword(password, encoded string) (bool, error) {
+	if strings.HasPrefix(encoded, "$argon2id$") {
+		h, err := ParsePHC(encoded)
+		if err != nil {
+			return false, err
+		}
+		key := argon2.IDKey([]byte(password), h.Salt, h.Params.Time, h.Params.Memory, h.Params.Threads, h.Params.KeyLength)
+		return subtle.ConstantTimeCompare(key, h.Key) == 1, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid hash: %w", err)
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestHashPassword_Bcrypt checks that the bcrypt hash verifies against the original password.
+func TestHashPassword_Bcrypt(t *testing.T) {
+	pwd := "x8#Kq2!vLm9@"
+	hash, err := HashPassword(pwd, HashBcrypt, HashParams{Cost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pwd)); err != nil {
+		t.Errorf("hash does not verify against password: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("wrong")); err == nil {
+		t.Error("hash unexpectedly verifies against a different password")
+	}
+}
+
+// TestHashPassword_Argon2id checks that the Argon2id hash uses the PHC format and a fresh salt.
+func TestHashPassword_Argon2id(t *testing.T) {
+	params := HashParams{Time: 1, Memory: 8 * 1024, Threads: 1}
+	a, err := HashPassword("secret", HashArgon2id, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := HashPassword("secret", HashArgon2id, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(a, "$argon2id$v=19$m=8192,t=1,p=1$") {
+		t.Errorf("unexpected hash format: %s", a)
+	}
+	if a == b {
+		t.Error("expected different hashes due to random salts")
+	}
+}
+
+// TestHashPassword_Invalid checks that unknown algorithms and empty passwords return an error.
+func TestHashPassword_Invalid(t *testing.T) {
+	if _, err := HashPassword("secret", HashAlgo("md5"), HashParams{}); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	if _, err := HashPassword("", HashBcrypt, HashParams{}); err == nil {
+		t.Error("expected error for empty password")
+	}
+	if _, err := ParseHashAlgo("sha1"); err == nil {
+		t.Error("expected error parsing unsupported algorithm")
+	}
+}
+
+// TestHashPHC_RoundTrip checks that a PHC string parses back to its parameters and verifies.
+func TestHashPHC_RoundTrip(t *testing.T) {
+	params := HashParams{Time: 1, Memory: 8 * 1024, Threads: 2, SaltLength: 8, KeyLength: 24}
+	encoded, err := HashPHC("secret", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h, err := ParsePHC(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Params != params {
+		t.Errorf("expected params %+v, got %+v", params, h.Params)
+	}
+	if len(h.Salt) != 8 || len(h.Key) != 24 {
+		t.Errorf("unexpected salt/key lengths %d/%d", len(h.Salt), len(h.Key))
+	}
+	if h.String() != encoded {
+		t.Errorf("expected re-encoding to match, got %s want %s", h.String(), encoded)
+	}
+
+	ok, err := VerifyPassword("secret", encoded)
+	if err != nil || !ok {
+		t.Errorf("expected password to verify, got %v, %v", ok, err)
+	}
+	ok, err = VerifyPassword("wrong", encoded)
+	if err != nil || ok {
+		t.Errorf("expected wrong password to fail, got %v, %v", ok, err)
+	}
+}
+
+// TestVerifyPassword_Bcrypt checks that bcrypt hashes verify and malformed hashes return an error.
+func TestVerifyPassword_Bcrypt(t *testing.T) {
+	hash, err := HashPassword("secret", HashBcrypt, HashParams{Cost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, err := VerifyPassword("secret", hash); err != nil || !ok {
+		t.Errorf("expected password to verify, got %v, %v", ok, err)
+	}
+	if ok, err := VerifyPassword("wrong", hash); err != nil || ok {
+		t.Errorf("expected wrong password to fail, got %v, %v", ok, err)
+	}
+	if _, err := VerifyPassword("secret", "$argon2id$v=19$m=x$salt$key"); err == nil {
+		t.Error("expected error for malformed PHC string")
+	}
+	if _, err := VerifyPassword("secret", "not-a-hash"); err == nil {
+		t.Error("expected error for malformed hash")
+	}
+}
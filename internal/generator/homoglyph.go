@@ -0,0 +1,73 @@
+package generator
+
+// homoglyphGroups lists characters from different scripts that render (nearly)
+// identically. The first rune of each group is the Latin representative.
+var homoglyphGroups = []string{
+	"aаɑα", // Latin a, Cyrillic a, Latin alpha, Greek alpha
+	"cс",   // Cyrillic es
+	"eе",   // Cyrillic ie
+	"iі",   // Cyrillic byelorussian-ukrainian i
+	"jј",   // Cyrillic je
+	"oоο",  // Cyrillic o, Greek omicron
+	"pрρ",  // Cyrillic er, Greek rho
+	"sѕ",   // Cyrillic dze
+	"xхχ",  // Cyrillic ha, Greek chi
+	"yу",   // Cyrillic u
+	"AАΑ",  // Cyrillic A, Greek Alpha
+	"BВΒ",  // Cyrillic Ve, Greek Beta
+	"CС",   // Cyrillic Es
+	"EЕΕ",  // Cyrillic Ie, Greek Epsilon
+	"HНΗ",  // Cyrillic En, Greek Eta
+	"IІΙ",  // Cyrillic I, Greek Iota
+	"JЈ",   // Cyrillic Je
+	"KКΚ",  // Cyrillic Ka, Greek Kappa
+	"MМΜ",  // Cyrillic Em, Greek Mu
+	"NΝ",   // Greek Nu
+	"OОΟ",  // Cyrillic O, Greek Omicron
+	"PРΡ",  // Cyrillic Er, Greek Rho
+	"SЅ",   // Cyrillic Dze
+	"TТΤ",  // Cyrillic Te, Greek Tau
+	"XХΧ",  // Cyrillic Ha, Greek Chi
+	"YΥ",   // Greek Upsilon
+	"ZΖ",   // Greek Zeta
+}
+
+// homoglyphKeys maps every rune in homoglyphGroups to its Latin representative.
+var homoglyphKeys = func() map[rune]rune {
+	keys := make(map[rune]rune)
+	for _, group := range homoglyphGroups {
+		runes := []rune(group)
+		for _, r := range runes {
+			keys[r] = runes[0]
+		}
+	}
+	return keys
+}()
+
+// homoglyphKey returns the representative of r's homoglyph group, or r itself.
+func homoglyphKey(r rune) rune {
+	if k, ok := homoglyphKeys[r]; ok {
+		return k
+	}
+	return r
+}
+
+// excludeHomoglyphs returns the runes of extra that are not confusable with a
+// rune of base or with an earlier rune of extra. With base "a" and extra "аб"
+// (Cyrillic), the Cyrillic "а" is dropped and "б" is kept.
+func excludeHomoglyphs(base, extra []rune) []rune {
+	seen := make(map[rune]bool)
+	for _, r := range base {
+		seen[homoglyphKey(r)] = true
+	}
+	var kept []rune
+	for _, r := range extra {
+		k := homoglyphKey(r)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		kept = append(kept, r)
+	}
+	return kept
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestExcludeHomoglyphs checks that Cyrillic "а" is excluded when Latin "a" is present.
+func TestExcludeHomoglyphs(t *testing.T) {
+	opt := PasswordOptions{
+		Length:            12,
+		UseLower:          true,
+		Count:             200,
+		ExtraChars:        "аб", // Cyrillic a and be
+		ExcludeHomoglyphs: true,
+	}
+	p := newPools(opt)
+	if slices.Contains(p.charset, 'а') {
+		t.Error("expected Cyrillic а to be excluded from the pool")
+	}
+	if !slices.Contains(p.charset, 'б') {
+		t.Error("expected Cyrillic б to stay in the pool")
+	}
+
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if strings.ContainsRune(gp.Value, 'а') {
+			t.Errorf("password %s contains Cyrillic а", gp.Value)
+		}
+	}
+
+	opt.ExcludeHomoglyphs = false
+	if p := newPools(opt); !slices.Contains(p.charset, 'а') {
+		t.Error("expected Cyrillic а in the pool when homoglyphs are allowed")
+	}
+}
+
+// TestExcludeHomoglyphs_AcrossExtras checks that confusable extras are deduplicated
+// among themselves when their Latin counterpart is not enabled.
+func TestExcludeHomoglyphs_AcrossExtras(t *testing.T) {
+	got := excludeHomoglyphs([]rune(numbers), []rune("аαб"))
+	if string(got) != "аб" {
+		t.Errorf("expected only the first of the confusable extras to be kept, got %q", string(got))
+	}
+}
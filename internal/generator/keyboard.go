@@ -0,0 +1,86 @@
+package generator
+
+import "strings"
+
+// qwertyRows describes the US QWERTY layout, one row per entry from the number
+// row down. Each row is listed unshifted and shifted; both characters of a key
+// share its physical position. The offset aligns the first key of a row with
+// the columns of the number row, whose first key is "`".
+var qwertyRows = [...]struct {
+	plain, shifted string
+	offset         int
+}{
+	{"`1234567890-=", "~!@#$%^&*()_+", 0},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|", 1},
+	{"asdfghjkl;'", "ASDFGHJKL:\"", 1},
+	{"zxcvbnm,./", "ZXCVBNM<>?", 1},
+}
+
+// keyPos is the row and column of a key on the QWERTY layout.
+type keyPos struct{ row, col int }
+
+// qwertyPositions maps every character on the QWERTY layout to its key position.
+var qwertyPositions = func() map[rune]keyPos {
+	positions := make(map[rune]keyPos)
+	for row, r := range qwertyRows {
+		for col, c := range r.plain {
+			positions[c] = keyPos{row, r.offset + col}
+		}
+		for col, c := range r.shifted {
+			positions[c] = keyPos{row, r.offset + col}
+		}
+	}
+	return positions
+}()
+
+// needsShift reports whether r is typed with Shift on a US QWERTY keyboard.
+// Characters that are not on the layout are not.
+func needsShift(r rune) bool {
+	for _, row := range qwertyRows {
+		if strings.ContainsRune(row.shifted, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyboardAdjacent reports whether a and b sit on neighbouring keys of a US
+// QWERTY keyboard, regardless of shift state. Because each row is staggered half
+// a key to the right of the one above, a key at (row, col) touches
+// (row, col±1), (row-1, col), (row-1, col+1), (row+1, col-1) and (row+1, col).
+// Characters that are not on the layout are never adjacent.
+func keyboardAdjacent(a, b rune) bool {
+	pa, ok := qwertyPositions[a]
+	if !ok {
+		return false
+	}
+	pb, ok := qwertyPositions[b]
+	if !ok {
+		return false
+	}
+
+	dr, dc := pb.row-pa.row, pb.col-pa.col
+	switch dr {
+	case 0:
+		return dc == 1 || dc == -1
+	case -1:
+		return dc == 0 || dc == 1
+	case 1:
+		return dc == -1 || dc == 0
+	default:
+		return false
+	}
+}
+
+// adjacentKeyPair returns the first pair of consecutive characters in s that sit
+// on neighbouring QWERTY keys, and whether one was found.
+func adjacentKeyPair(s string) (rune, rune, bool) {
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 && keyboardAdjacent(prev, r) {
+			return prev, r, true
+		}
+		prev = r
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestKeyboardAdjacent checks the QWERTY adjacency map on known pairs.
+func TestKeyboardAdjacent(t *testing.T) {
+	adjacent := []string{"qw", "wq", "q1", "q2", "qa", "as", "aw", "az", "sz", "zx", "Qw", "!2", "p[", "l;", ";'"}
+	for _, pair := range adjacent {
+		r := []rune(pair)
+		if !keyboardAdjacent(r[0], r[1]) {
+			t.Errorf("expected %q to be adjacent", pair)
+		}
+	}
+	separate := []string{"qe", "q3", "ax", "zq", "aa", "1a", "é1"}
+	for _, pair := range separate {
+		r := []rune(pair)
+		if keyboardAdjacent(r[0], r[1]) {
+			t.Errorf("expected %q not to be adjacent", pair)
+		}
+	}
+}
+
+// TestGeneratePassword_AvoidKeyboardAdjacent checks that no generated password
+// contains two consecutive characters on neighbouring keys.
+func TestGeneratePassword_AvoidKeyboardAdjacent(t *testing.T) {
+	opt := PasswordOptions{
+		Length:                16,
+		UseSpecialChars:       true,
+		UseNumbers:            true,
+		UseUpper:              true,
+		UseLower:              true,
+		Count:                 200,
+		AvoidKeyboardAdjacent: true,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if a, b, ok := adjacentKeyPair(gp.Value); ok {
+			t.Errorf("password %s contains adjacent keys %c%c", gp.Value, a, b)
+		}
+	}
+}
+
+// TestGeneratePassword_NoShift checks that no character needing Shift appears,
+// that entropy counts the reduced set, and that uppercase letters are rejected.
+func TestGeneratePassword_NoShift(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseLower:        true,
+		Count:           200,
+		ExtraChars:      "A!é",
+		NoShift:         true,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 16 * math.Log2(float64(26+10+len(unshiftedSymbols)+1)) // "é" is kept
+	for _, gp := range passwords {
+		for _, r := range gp.Value {
+			if needsShift(r) {
+				t.Errorf("password %s contains %q, which needs Shift", gp.Value, r)
+			}
+		}
+		if math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected entropy %.2f, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	opt.UseUpper = true
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when uppercase letters are enabled")
+	}
+}
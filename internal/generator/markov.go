@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"errors"
+	"slices"
+	"sort"
+)
+
+// MarkovModel is a character-level Markov chain trained on a corpus of
+// passwords. Each state is the preceding order runes (fewer at the start of a
+// password) and maps to the observed next runes and their counts.
+type MarkovModel struct {
+	order  int
+	states map[string]*markovState
+}
+
+// markovState holds the possible next runes of a context in a fixed order,
+// with cumulative counts for weighted sampling.
+type markovState struct {
+	next  []rune
+	cum   []int // cum[i] is the total count of next[0..i]
+	total int
+}
+
+// Order returns the number of preceding runes the model conditions on.
+func (m *MarkovModel) Order() int {
+	return m.order
+}
+
+// TrainMarkov builds a character-level Markov model of the given order from
+// corpus. Higher orders reproduce the corpus more closely.
+func TrainMarkov(corpus []string, order int) (*MarkovModel, error) {
+	if order < 1 {
+		return nil, errors.New("order must be greater than 0")
+	}
+
+	counts := make(map[string]map[rune]int)
+	for _, word := range corpus {
+		runes := []rune(word)
+		for i, r := range runes {
+			ctx := string(runes[max(0, i-order):i])
+			if counts[ctx] == nil {
+				counts[ctx] = make(map[rune]int)
+			}
+			counts[ctx][r]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil, errors.New("corpus contains no characters")
+	}
+
+	m := &MarkovModel{order: order, states: make(map[string]*markovState, len(counts))}
+	for ctx, next := range counts {
+		s := &markovState{next: make([]rune, 0, len(next))}
+		for r := range next {
+			s.next = append(s.next, r)
+		}
+		slices.Sort(s.next)
+		for _, r := range s.next {
+			s.total += next[r]
+			s.cum = append(s.cum, s.total)
+		}
+		m.states[ctx] = s
+	}
+	return m, nil
+}
+
+// GenerateMarkov generates count strings of length runes by walking model,
+// choosing each rune with the probability observed in the training corpus.
+// When a context was never followed by anything, the oldest rune of the
+// context is dropped until a known context is found.
+//
+// The output only resembles human-chosen passwords and has far less entropy
+// than uniform generation. Use it for realistic test data, never for real
+// credentials.
+func GenerateMarkov(model *MarkovModel, length, count int) ([]string, error) {
+	if model == nil || len(model.states) == 0 {
+		return nil, errors.New("markov model is empty")
+	}
+	if length < 1 {
+		return nil, errors.New("length must be greater than 0")
+	}
+	if count < 1 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	results := make([]string, count)
+	for i := range results {
+		out := make([]rune, 0, length)
+		for len(out) < length {
+			r, err := model.next(out)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+		results[i] = string(out)
+	}
+	return results, nil
+}
+
+// next draws the rune that follows prev.
+func (m *MarkovModel) next(prev []rune) (rune, error) {
+	ctx := prev[max(0, len(prev)-m.order):]
+	s, ok := m.states[string(ctx)]
+	for !ok || s.total == 0 {
+		if len(ctx) == 0 {
+			return 0, errors.New("markov model has no starting state")
+		}
+		ctx = ctx[1:]
+		s, ok = m.states[string(ctx)]
+	}
+
+	n, err := secureRandomInt(s.total)
+	if err != nil {
+		return 0, err
+	}
+	return s.next[sort.SearchInts(s.cum, n+1)], nil
+}
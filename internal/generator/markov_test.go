@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateMarkov_CorpusCharacters checks that generated strings only use
+// characters seen in the training corpus and have the requested length.
+func TestGenerateMarkov_CorpusCharacters(t *testing.T) {
+	corpus := []string{"sunshine1", "dragon99", "letmein!", "monkey123", "Summer2024"}
+	model, err := TrainMarkov(corpus, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := strings.Join(corpus, "")
+
+	out, err := GenerateMarkov(model, 12, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 200 {
+		t.Fatalf("expected 200 strings, got %d", len(out))
+	}
+	for _, s := range out {
+		if len([]rune(s)) != 12 {
+			t.Errorf("expected length 12, got %q", s)
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(seen, r) {
+				t.Errorf("string %q contains %q, which is not in the corpus", s, r)
+			}
+		}
+	}
+}
+
+// TestGenerateMarkov_Deterministic checks that a corpus with a single transition
+// per context is reproduced exactly, including the back-off at its end.
+func TestGenerateMarkov_Deterministic(t *testing.T) {
+	model, err := TrainMarkov([]string{"abc"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// After "c" there is no transition, so the walk backs off to the start state.
+	out, err := GenerateMarkov(model, 7, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out[0] != "abcabca" {
+		t.Errorf("expected abcabca, got %s", out[0])
+	}
+}
+
+// TestTrainMarkov_Invalid checks that invalid training input and models return an error.
+func TestTrainMarkov_Invalid(t *testing.T) {
+	if _, err := TrainMarkov([]string{"abc"}, 0); err == nil {
+		t.Error("expected error for order 0")
+	}
+	if _, err := TrainMarkov([]string{"", ""}, 2); err == nil {
+		t.Error("expected error for empty corpus")
+	}
+	if _, err := GenerateMarkov(nil, 8, 1); err == nil {
+		t.Error("expected error for nil model")
+	}
+}
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// GenerateMatching generates passwords like GeneratePassword and regenerates
+// each candidate until its complete value matches pattern, for sites that
+// publish their password rules as a regular expression. It gives up with an
+// error after the usual bound of attempts per password.
+//
+// Every candidate is generated blindly and then tested, so patterns that few
+// random passwords match, such as ones requiring several specific characters,
+// can be slow or fail. Anchor the pattern with ^ and $ to constrain the whole
+// password.
+func GenerateMatching(pattern *regexp.Regexp, opt PasswordOptions) ([]GeneratedPassword, error) {
+	if pattern == nil {
+		return nil, errors.New("pattern is nil")
+	}
+	reject := opt.Reject
+	opt.Reject = func(value string) bool {
+		if !pattern.MatchString(value) {
+			return true
+		}
+		return reject != nil && reject(value)
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		return nil, fmt.Errorf("no password matching %s: %w", pattern, err)
+	}
+	return passwords, nil
+}
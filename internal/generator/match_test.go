@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestGenerateMatching checks that every password matches a site rule and that an
+// unmatchable pattern returns an error.
+func TestGenerateMatching(t *testing.T) {
+	// Ten characters starting with a letter and containing a digit.
+	pattern := regexp.MustCompile(`^[A-Za-z].*[0-9]`)
+	opt := PasswordOptions{
+		Length:          10,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           100,
+	}
+	passwords, err := GenerateMatching(pattern, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if !pattern.MatchString(gp.Value) || len(gp.Value) != 10 {
+			t.Errorf("password %s does not match %s", gp.Value, pattern)
+		}
+	}
+
+	if _, err := GenerateMatching(regexp.MustCompile(`^[0-9]+$`), PasswordOptions{Length: 8, UseLower: true, Count: 1}); err == nil {
+		t.Error("expected error for a pattern no candidate can match")
+	}
+	if _, err := GenerateMatching(nil, opt); err == nil {
+		t.Error("expected error for a nil pattern")
+	}
+}
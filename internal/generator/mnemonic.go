@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// mnemonicSizes maps each entropy size in bits allowed by BIP39 to the number
+// of words in the resulting mnemonic.
+var mnemonicSizes = map[int]int{128: 12, 160: 15, 192: 18, 224: 21, 256: 24}
+
+// MnemonicBits returns the entropy size in bits of a BIP39 mnemonic with the
+// given number of words, which must be 12, 15, 18, 21 or 24.
+func MnemonicBits(words int) (int, error) {
+	for bits, n := range mnemonicSizes {
+		if n == words {
+			return bits, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid mnemonic word count %d (expected 12, 15, 18, 21 or 24)", words)
+}
+
+// GenerateMnemonic generates a BIP39 mnemonic from bits of fresh secure
+// randomness, using the standard English wordlist and checksum. bits must be
+// 128, 160, 192, 224 or 256, giving 12 to 24 words.
+func GenerateMnemonic(bits int) (string, error) {
+	if _, ok := mnemonicSizes[bits]; !ok {
+		return "", fmt.Errorf("invalid mnemonic entropy size %d (expected 128, 160, 192, 224 or 256 bits)", bits)
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
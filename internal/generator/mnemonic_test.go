@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// TestGenerateMnemonic checks the word count and BIP39 checksum for every allowed size.
+func TestGenerateMnemonic(t *testing.T) {
+	for bits, words := range mnemonicSizes {
+		mnemonic, err := GenerateMnemonic(bits)
+		if err != nil {
+			t.Fatalf("unexpected error for %d bits: %v", bits, err)
+		}
+		if n := len(strings.Fields(mnemonic)); n != words {
+			t.Errorf("expected %d words for %d bits, got %d", words, bits, n)
+		}
+		if !bip39.IsMnemonicValid(mnemonic) {
+			t.Errorf("mnemonic %q fails BIP39 checksum validation", mnemonic)
+		}
+	}
+	if bits, err := MnemonicBits(18); err != nil || bits != 192 {
+		t.Errorf("expected 192 bits for 18 words, got %d (%v)", bits, err)
+	}
+	if _, err := MnemonicBits(13); err == nil {
+		t.Error("expected error for 13 words")
+	}
+	for _, bits := range []int{0, 64, 129, 512} {
+		if _, err := GenerateMnemonic(bits); err == nil {
+			t.Errorf("expected error for %d bits", bits)
+		}
+	}
+}
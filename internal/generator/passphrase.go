@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// PassphraseOptions defines the options for passphrase generation.
+type PassphraseOptions struct {
+	Words     int    `json:"words"`     // Number of words in each passphrase
+	Separator string `json:"separator"` // Placed between words
+	Count     int    `json:"count"`     // Number of passphrases to generate
+
+	// Wordlist is the list words are drawn from. Nil selects the 2048-word
+	// BIP39 English list, which gives 11 bits of entropy per word.
+	Wordlist []string `json:"-"`
+
+	// WeightedByFrequency draws words in proportion to their weight in
+	// Frequencies rather than uniformly, so common words come up more often.
+	// This trades entropy for memorability: the reported entropy is the
+	// Shannon entropy of the weighted distribution times Words. Words of the
+	// list missing from Frequencies are never drawn, and every key must be a
+	// word of the list.
+	WeightedByFrequency bool               `json:"weighted_by_frequency"`
+	Frequencies         map[string]float64 `json:"-"`
+}
+
+// wordlist returns the word list selected by opt.
+func (opt PassphraseOptions) wordlist() []string {
+	if opt.Wordlist == nil {
+		return wordlists.English
+	}
+	return opt.Wordlist
+}
+
+// validatePassphraseOptions checks if the provided PassphraseOptions are valid.
+func validatePassphraseOptions(opt PassphraseOptions) error {
+	if opt.Words < 1 {
+		return errors.New("word count must be greater than 0")
+	}
+	if opt.Count < 1 {
+		return errors.New("count must be greater than 0")
+	}
+	for _, r := range opt.Separator {
+		if unicode.IsControl(r) {
+			return errors.New("separator must be printable")
+		}
+	}
+	if err := validateWordlist(opt.wordlist()); err != nil {
+		return err
+	}
+	if opt.WeightedByFrequency {
+		return validateFrequencies(opt.wordlist(), opt.Frequencies)
+	}
+	return nil
+}
+
+// validateFrequencies checks that freq assigns finite, non-negative weights
+// to words of list only, and a positive weight to at least two of them.
+func validateFrequencies(list []string, freq map[string]float64) error {
+	words := make(map[string]bool, len(list))
+	for _, w := range list {
+		words[w] = true
+	}
+	positive := 0
+	for w, f := range freq {
+		if !words[w] {
+			return fmt.Errorf("frequency table word %q is not in the wordlist", w)
+		}
+		if f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("frequency of %q must be a non-negative number", w)
+		}
+		if f > 0 {
+			positive++
+		}
+	}
+	if positive < 2 {
+		return errors.New("frequency table must give a positive weight to at least two words")
+	}
+	return nil
+}
+
+// wordProbabilities returns the probability of drawing each word of list in
+// a passphrase weighted by freq.
+func wordProbabilities(list []string, freq map[string]float64) []float64 {
+	var total float64
+	for _, w := range list {
+		total += freq[w]
+	}
+	p := make([]float64, len(list))
+	for i, w := range list {
+		p[i] = freq[w] / total
+	}
+	return p
+}
+
+// weightedResolution is the number of equally likely outcomes a weighted word
+// draw is made from, the precision of a float64 mantissa.
+const weightedResolution = 1 << 53
+
+// drawWeighted returns an index into p drawn with the given probabilities.
+func drawWeighted(p []float64) (int, error) {
+	n, err := secureRandomInt(weightedResolution)
+	if err != nil {
+		return 0, err
+	}
+	u := float64(n) / weightedResolution
+	last := 0
+	for i, pi := range p {
+		if pi == 0 {
+			continue
+		}
+		if u < pi {
+			return i, nil
+		}
+		u -= pi
+		last = i
+	}
+	// Rounding left u just above the total; the draw belongs to the last word.
+	return last, nil
+}
+
+// validateWordlist checks that list holds at least two distinct, non-empty,
+// printable words without surrounding whitespace.
+func validateWordlist(list []string) error {
+	if len(list) < 2 {
+		return errors.New("wordlist must contain at least two words")
+	}
+	seen := make(map[string]bool, len(list))
+	for _, w := range list {
+		if w == "" || strings.TrimSpace(w) != w || strings.ContainsFunc(w, unicode.IsControl) {
+			return errors.New("wordlist entries must be non-empty, printable and without surrounding whitespace")
+		}
+		if seen[w] {
+			return errors.New("wordlist contains duplicate words")
+		}
+		seen[w] = true
+	}
+	return nil
+}
+
+// PassphraseEntropy returns the entropy in bits of a passphrase generated with
+// opt: Words * log2(wordlist size). The separator adds none.
+func PassphraseEntropy(opt PassphraseOptions) (float64, error) {
+	if err := validatePassphraseOptions(opt); err != nil {
+		return 0, err
+	}
+	if opt.WeightedByFrequency {
+		var perWord float64
+		for _, p := range wordProbabilities(opt.wordlist(), opt.Frequencies) {
+			if p > 0 {
+				perWord -= p * math.Log2(p)
+			}
+		}
+		return float64(opt.Words) * perWord, nil
+	}
+	return float64(opt.Words) * math.Log2(float64(len(opt.wordlist()))), nil
+}
+
+// WordsForEntropy returns the smallest number of words drawn from a list of
+// listSize words that reaches at least bits of entropy, or 0 if listSize is
+// below 2.
+func WordsForEntropy(bits float64, listSize int) int {
+	if listSize < 2 {
+		return 0
+	}
+	return max(int(math.Ceil(bits/math.Log2(float64(listSize)))), 1)
+}
+
+// GeneratePassphrase generates opt.Count passphrases of opt.Words words, each
+// drawn independently from the word list, uniformly unless
+// opt.WeightedByFrequency is set, and joined with opt.Separator. The result never starts or ends with whitespace.
+func GeneratePassphrase(opt PassphraseOptions) ([]GeneratedPassword, error) {
+	entropy, err := PassphraseEntropy(opt)
+	if err != nil {
+		return nil, err
+	}
+	list := opt.wordlist()
+	var probabilities []float64
+	if opt.WeightedByFrequency {
+		probabilities = wordProbabilities(list, opt.Frequencies)
+	}
+
+	passphrases := make([]GeneratedPassword, opt.Count)
+	for i := range passphrases {
+		words := make([]string, opt.Words)
+		for j := range words {
+			var n int
+			if probabilities != nil {
+				n, err = drawWeighted(probabilities)
+			} else {
+				n, err = secureRandomInt(len(list))
+			}
+			if err != nil {
+				return nil, err
+			}
+			words[j] = list[n]
+		}
+		passphrases[i] = GeneratedPassword{
+			Value:    strings.Join(words, opt.Separator),
+			Strength: classifyEntropy(entropy),
+			Entropy:  entropy,
+		}
+	}
+	return passphrases, nil
+}
+
+// LoadWordlist reads a word list with one word per line from r. Surrounding
+// whitespace and blank lines are ignored.
+func LoadWordlist(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if w := strings.TrimSpace(scanner.Text()); w != "" {
+			words = append(words, w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+	return words, nil
+}
+
+// MergeWordlists returns the union of lists, such as an English and a Turkish
+// list for multilingual passphrases, in order of first appearance. Words that
+// appear in several lists are kept once, so a passphrase drawn from the union
+// gets log2 of the deduplicated size in entropy per word.
+func MergeWordlists(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, list := range lists {
+		for _, w := range list {
+			if !seen[w] {
+				seen[w] = true
+				merged = append(merged, w)
+			}
+		}
+	}
+	return merged
+}
+
+// BIP39Wordlist returns the bundled BIP39 word list for language, one of
+// english, spanish, french, italian and czech.
+func BIP39Wordlist(language string) ([]string, error) {
+	switch language {
+	case "english":
+		return wordlists.English, nil
+	case "spanish":
+		return wordlists.Spanish, nil
+	case "french":
+		return wordlists.French, nil
+	case "italian":
+		return wordlists.Italian, nil
+	case "czech":
+		return wordlists.Czech, nil
+	default:
+		return nil, fmt.Errorf("unknown BIP39 wordlist %q (expected english, spanish, french, italian or czech)", language)
+	}
+}
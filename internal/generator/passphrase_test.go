@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestGeneratePassphrase checks the word count, separator and entropy of passphrases.
+func TestGeneratePassphrase(t *testing.T) {
+	opt := PassphraseOptions{Words: WordsForEntropy(80, 2048), Separator: "-", Count: 20}
+	if opt.Words != 8 {
+		t.Fatalf("expected 8 words for 80 bits from 2048 words, got %d", opt.Words)
+	}
+	passphrases, err := GeneratePassphrase(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list := opt.wordlist()
+	for _, gp := range passphrases {
+		words := strings.Split(gp.Value, "-")
+		if len(words) != 8 {
+			t.Errorf("expected 8 words, got %q", gp.Value)
+		}
+		for _, w := range words {
+			if !slices.Contains(list, w) {
+				t.Errorf("word %q is not in the wordlist", w)
+			}
+		}
+		if gp.Entropy != 88 {
+			t.Errorf("expected 88 bits, got %.2f", gp.Entropy)
+		}
+	}
+}
+
+// TestGeneratePassphrase_Invalid checks that invalid options and wordlists return an error.
+func TestGeneratePassphrase_Invalid(t *testing.T) {
+	cases := []PassphraseOptions{
+		{Words: 0, Count: 1},
+		{Words: 4, Count: 0},
+		{Words: 4, Count: 1, Wordlist: []string{"only"}},
+		{Words: 4, Count: 1, Wordlist: []string{"a", "a"}},
+		{Words: 4, Count: 1, Wordlist: []string{"a", " b"}},
+		{Words: 4, Count: 1, Separator: "\n"},
+		{Words: 4, Count: 1, WeightedByFrequency: true},
+		{Words: 4, Count: 1, Wordlist: []string{"a", "b"}, WeightedByFrequency: true, Frequencies: map[string]float64{"a": 1, "c": 1}},
+		{Words: 4, Count: 1, Wordlist: []string{"a", "b"}, WeightedByFrequency: true, Frequencies: map[string]float64{"a": 1, "b": -1}},
+		{Words: 4, Count: 1, Wordlist: []string{"a", "b"}, WeightedByFrequency: true, Frequencies: map[string]float64{"a": 1}},
+	}
+	for _, opt := range cases {
+		if _, err := GeneratePassphrase(opt); err == nil {
+			t.Errorf("expected error for %+v", opt)
+		}
+	}
+}
+
+// TestGeneratePassphrase_WeightedByFrequency checks that common words are drawn
+// more often and that entropy is the Shannon entropy of the weights.
+func TestGeneratePassphrase_WeightedByFrequency(t *testing.T) {
+	opt := PassphraseOptions{
+		Words:               4,
+		Separator:           " ",
+		Count:               2000,
+		Wordlist:            []string{"the", "house", "zephyr", "quixotic"},
+		WeightedByFrequency: true,
+		Frequencies:         map[string]float64{"the": 8, "house": 4, "zephyr": 2, "quixotic": 2},
+	}
+	passphrases, err := GeneratePassphrase(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, gp := range passphrases {
+		for _, w := range strings.Fields(gp.Value) {
+			counts[w]++
+		}
+	}
+	// 8000 draws: "the" expects 4000, "house" 2000, the rare words 1000 each.
+	if counts["the"] < 3600 || counts["house"] < 1700 || counts["house"] > 2300 {
+		t.Errorf("unexpected word counts: %v", counts)
+	}
+	if counts["the"] <= counts["house"] || counts["house"] <= counts["zephyr"] || counts["house"] <= counts["quixotic"] {
+		t.Errorf("expected common words to appear more often, got %v", counts)
+	}
+	// Per word: 0.5*1 + 0.25*2 + 2*0.125*3 = 1.75 bits.
+	if want := 4 * 1.75; passphrases[0].Entropy != want {
+		t.Errorf("expected %.2f bits, got %.2f", want, passphrases[0].Entropy)
+	}
+}
+
+// TestMergeWordlists checks that passphrases draw from the union of two lists,
+// with entropy over the deduplicated size.
+func TestMergeWordlists(t *testing.T) {
+	english, err := LoadWordlist(strings.NewReader("apple\nbread\n\n  water \nkitap\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	turkish, err := LoadWordlist(strings.NewReader("elma\nekmek\nsu\nkitap\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	merged := MergeWordlists(english, turkish)
+	want := []string{"apple", "bread", "water", "kitap", "elma", "ekmek", "su"}
+	if !slices.Equal(merged, want) {
+		t.Fatalf("expected %v, got %v", want, merged)
+	}
+
+	opt := PassphraseOptions{Words: 4, Separator: " ", Count: 200, Wordlist: merged}
+	passphrases, err := GeneratePassphrase(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fromEnglish, fromTurkish bool
+	for _, gp := range passphrases {
+		for _, w := range strings.Fields(gp.Value) {
+			fromEnglish = fromEnglish || slices.Contains(english[:3], w)
+			fromTurkish = fromTurkish || slices.Contains(turkish[:3], w)
+		}
+		if want := 4 * math.Log2(7); math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected %.2f bits over 7 words, got %.2f", want, gp.Entropy)
+		}
+	}
+	if !fromEnglish || !fromTurkish {
+		t.Errorf("expected words from both lists, got English %v and Turkish %v", fromEnglish, fromTurkish)
+	}
+
+	if _, err := BIP39Wordlist("klingon"); err == nil {
+		t.Error("expected error for an unknown BIP39 wordlist")
+	}
+}
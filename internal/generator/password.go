@@ -1,13 +1,24 @@
 // Package generator provides password generation and strength analysis utilities.
+//
+// Its functions are safe for concurrent use; see GeneratePassword for the details.
 package generator
 
 import (
+	"cmp"
 	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -15,16 +26,276 @@ const (
 	numbers      = "0123456789"
 	uppercase    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	lowercase    = "abcdefghijklmnopqrstuvwxyz"
+
+	// fullASCIISymbols is every printable ASCII punctuation character
+	// (0x21-0x7E without letters and digits), a superset of specialChars.
+	fullASCIISymbols = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	// xmlUnsafeChars are the characters XML and HTML escape as predefined
+	// entities (&lt; &gt; &amp; &quot; &apos;).
+	xmlUnsafeChars = "<>&\"'"
+	// unshiftedSymbols are the special characters typed without Shift on a US
+	// QWERTY keyboard, used with PasswordOptions.NoShift.
+	unshiftedSymbols = "-=[];',./"
+	// defaultAmbiguousChars are the characters PasswordOptions.ExcludeAmbiguous
+	// removes unless AmbiguousChars is set.
+	defaultAmbiguousChars = "0O1lI|"
+	// identifierSymbol is the only special character allowed in identifiers,
+	// used with PasswordOptions.IdentifierSafe.
+	identifierSymbol = "_"
+)
+
+// DefaultMaxLength is the largest Length accepted when
+// PasswordOptions.MaxLength is 0.
+const DefaultMaxLength = 4096
+
+var (
+	// prefixPattern matches the characters allowed in PasswordOptions.Prefix.
+	prefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+	// qaMarkerPattern matches the characters allowed in PasswordOptions.QAMarker.
+	qaMarkerPattern = regexp.MustCompile(`^[A-Za-z0-9]*$`)
 )
 
 // PasswordOptions defines the options for password generation.
 type PasswordOptions struct {
-	Length          int  // Length of each generated password
-	UseSpecialChars bool // Include special characters
-	UseNumbers      bool // Include numbers
-	UseUpper        bool // Include uppercase letters
-	UseLower        bool // Include lowercase letters
-	Count           int  // Number of passwords to generate
+	Length          int  `json:"length"`            // Length of each generated password
+	UseSpecialChars bool `json:"use_special_chars"` // Include special characters
+	UseNumbers      bool `json:"use_numbers"`       // Include numbers
+	UseUpper        bool `json:"use_upper"`         // Include uppercase letters
+	UseLower        bool `json:"use_lower"`         // Include lowercase letters
+	Count           int  `json:"count"`             // Number of passwords to generate
+
+	// MaxLength caps Length to guard against accidental huge allocations,
+	// such as a mistyped length of a billion. 0 selects DefaultMaxLength;
+	// raise it to generate longer passwords.
+	MaxLength int `json:"max_length"`
+
+	// SpecialFrequency, when greater than zero, is the probability (0-1) that a
+	// fill position is drawn from the special characters rather than from the
+	// other enabled sets. It biases the character distribution, so the reported
+	// entropy, which assumes uniform draws, overstates the real strength.
+	SpecialFrequency float64 `json:"special_frequency"`
+
+	// UpperRatio, when greater than zero, is the probability (0-1) that a
+	// letter drawn for a fill position is uppercase, for mostly-uppercase or
+	// mostly-lowercase looks. It requires both letter cases and, like
+	// SpecialFrequency, biases the distribution the reported entropy assumes
+	// uniform. The guaranteed letter of each case is still placed.
+	UpperRatio float64 `json:"upper_ratio"`
+
+	MaxUpper   int `json:"max_upper"`   // Maximum number of uppercase letters (0 means unlimited)
+	MaxLower   int `json:"max_lower"`   // Maximum number of lowercase letters (0 means unlimited)
+	MaxNumbers int `json:"max_numbers"` // Maximum number of digits (0 means unlimited)
+	MaxSpecial int `json:"max_special"` // Maximum number of special characters (0 means unlimited)
+
+	// FullASCIISymbols replaces the curated special characters with all 32
+	// printable ASCII punctuation characters, including quotes, backslash and
+	// backtick, for the maximum symbol entropy. Such passwords may need quoting
+	// or escaping in shells and configuration files.
+	FullASCIISymbols bool `json:"full_ascii_symbols"`
+
+	// SpecialChars, when set, replaces the built-in special characters with a
+	// custom set of printable ASCII punctuation, which may include a space.
+	// Generated passwords never start or end with whitespace, whatever the
+	// character set, so that it cannot be lost when copying and pasting.
+	SpecialChars string `json:"special_chars"`
+
+	// AvoidOldChars lists characters removed from every character set, such as
+	// the characters of the password being rotated out, so that none of them
+	// appears in the new password. It is an error if this empties an enabled set.
+	AvoidOldChars string `json:"-"`
+
+	// ExcludeAmbiguous removes characters that are easily confused when read,
+	// by default 0 O 1 l I and |, from every set. Which characters look alike
+	// depends on the font, so AmbiguousChars, when set, replaces that list.
+	ExcludeAmbiguous bool   `json:"exclude_ambiguous"`
+	AmbiguousChars   string `json:"ambiguous_chars"`
+
+	// NoShift restricts passwords to characters typed without Shift on a US
+	// QWERTY keyboard, for one-handed or accessible typing: lowercase letters,
+	// digits and the symbols -=[];',./ (replacing the special set, even with
+	// FullASCIISymbols). Extra characters that need Shift are dropped. Uppercase
+	// letters cannot be enabled.
+	NoShift bool `json:"no_shift"`
+
+	// XMLSafe removes the characters XML and HTML escape (< > & " ') from
+	// every set, so passwords can be embedded in config files verbatim. The
+	// reported entropy counts the reduced set.
+	XMLSafe bool `json:"xml_safe"`
+
+	// IdentifierSafe restricts passwords to [A-Za-z0-9_] and never starts them
+	// with a digit, so they are valid Go and shell identifiers for generated
+	// variable or container names. The special set becomes "_" and other extra
+	// characters are dropped; the reported entropy counts the reduced set.
+	// Prefix may then not contain '-', neither Prefix nor QAMarker may start with a digit,
+	// and DateToken and RealSeparator cannot be used.
+	IdentifierSafe bool `json:"identifier_safe"`
+
+	// CaseInsensitive marks passwords for systems that fold letter case, where
+	// "a" and "A" are the same character. Only one of UseUpper and UseLower may
+	// then be set, and extra characters that fold onto a pool character are
+	// dropped, so the reported entropy counts 26 letters rather than 52.
+	CaseInsensitive bool `json:"case_insensitive"`
+
+	NoLeadingSpecial  bool `json:"no_leading_special"`  // Never start the password with a special character
+	NoTrailingSpecial bool `json:"no_trailing_special"` // Never end the password with a special character
+
+	// AvoidKeyboardAdjacent regenerates passwords in which two consecutive
+	// characters sit on neighbouring keys of a US QWERTY keyboard, such as "qw"
+	// or "3E", to make them less error-prone to type. Other layouts are not
+	// considered.
+	AvoidKeyboardAdjacent bool `json:"avoid_keyboard_adjacent"`
+
+	// NoSequential regenerates passwords containing an ascending or descending
+	// run of three or more characters within A-Z, a-z or 0-9, such as "abc" or
+	// "321". Runs do not cross sets: "aBc" and "9ab" are allowed.
+	NoSequential bool `json:"no_sequential"`
+
+	// NoRepeatedBigrams regenerates passwords in which any two-character
+	// sequence occurs more than once, such as "ab" in "xabyab". Lengths that a
+	// small pool is unlikely to fill without a repeat are rejected, e.g. more
+	// than 36 characters from the 10 digits.
+	NoRepeatedBigrams bool `json:"no_repeated_bigrams"`
+
+	// ScatterDigits regenerates passwords that end in a run of digits, the
+	// predictable structure of human choices like "Password1234".
+	ScatterDigits bool `json:"scatter_digits"`
+
+	MinDistinct int `json:"min_distinct"` // Minimum number of distinct characters (0 means no minimum)
+
+	// MinClasses, when positive, replaces the guarantee of one character from
+	// every selected set with one from each of MinClasses sets chosen at random
+	// per password, for policies such as "at least 3 of 4 character types".
+	MinClasses int `json:"min_classes"`
+
+	// BalanceCase regenerates passwords whose uppercase and lowercase counts
+	// differ by more than CaseTolerance. It requires both sets to be enabled.
+	BalanceCase   bool `json:"balance_case"`
+	CaseTolerance int  `json:"case_tolerance"` // Maximum upper/lower count difference with BalanceCase (values < 1 mean 1)
+
+	MaxSharedFraction float64 `json:"max_shared_fraction"` // Rotation only: maximum fraction of positions shared with the old password (0 uses the default)
+
+	Policies []Policy `json:"policies"` // Policies every generated password must satisfy
+
+	// ExtraChars adds characters beyond the built-in sets to the fill pool, such
+	// as Cyrillic letters or emoji. Unlike the built-in sets, none of them is
+	// guaranteed to appear.
+	ExtraChars string `json:"extra_chars"`
+	// ExcludeHomoglyphs drops extra characters that look like a character already
+	// in the pool, such as Cyrillic "а" when Latin "a" is enabled.
+	ExcludeHomoglyphs bool `json:"exclude_homoglyphs"`
+	// RequireCategory lists Unicode general categories, such as "Lu", "Nd" or
+	// "So", that must each be represented by at least one rune. The runes are
+	// drawn from ExtraChars, which must contain a rune of every category.
+	RequireCategory []string `json:"require_category"`
+	// RequireFromGroups lists groups of characters, such as "!@#" and "$%^",
+	// that must each be represented by at least one character, on top of the
+	// per-set guarantees. Group characters outside the pool are ignored, and
+	// every group must keep at least one.
+	RequireFromGroups CharGroups `json:"require_from_groups"`
+
+	// Seed, when set, makes generation deterministic: the same seed and options
+	// always produce the same passwords. Anyone holding the seed can reproduce
+	// them, so seeded passwords must never be used as real credentials. It is
+	// never encoded to JSON, so printed configurations do not leak it.
+	Seed []byte `json:"-"`
+
+	// Prefix is prepended to each password as a human-readable tag, e.g. "aws-".
+	// It may only contain letters, digits, '-' and '_'. Length, the character set
+	// requirements and the reported entropy apply to the random part only.
+	// The prefix is not secret, so it adds no strength to the password.
+	Prefix string `json:"prefix"`
+
+	// DateToken is a format expanded with ExpandDateToken at generation time
+	// and prepended to the random part (after Prefix), e.g. "%YQ%q-" for
+	// "2024Q1-". With DateTokenAppend it is appended instead. The token is
+	// predictable, so it reduces secrecy: it is not counted toward Length or
+	// the reported entropy. Use it only in controlled environments.
+	DateToken       string `json:"date_token"`
+	DateTokenAppend bool   `json:"date_token_append"`
+
+	// QAMarker is inserted at a random position of the random part to mark the
+	// password as test data that QA staff can recognize, e.g. "Qa". It may only
+	// contain letters and digits from the character set, so it never breaks a
+	// validator, and the marked password must still meet every constraint,
+	// such as the maximum counts and policies. Like Prefix, it is not counted
+	// toward Length or the reported entropy. Never use marked passwords as real
+	// credentials.
+	QAMarker string `json:"qa_marker"`
+
+	// RealSeparator, when SeparatorEvery is positive, is inserted after every
+	// SeparatorEvery characters of the random part as a real character of the
+	// password, for systems that store and count dashes. Unlike FormatGrouped,
+	// which only formats a password for display, the separators are part of
+	// the value. They are fixed, so they add no entropy and are not counted
+	// toward Length. The separator may not be whitespace, a pool character, a
+	// character excluded by AvoidOldChars, XMLSafe or ExcludeAmbiguous, or,
+	// with NoShift, a character that needs Shift.
+	RealSeparator  rune `json:"real_separator"`
+	SeparatorEvery int  `json:"separator_every"`
+
+	// Reject, when set, is called with each complete candidate value, including
+	// Prefix, DateToken and QAMarker, once every other constraint holds.
+	// Returning true discards the candidate and generates another, e.g. to avoid
+	// values issued by earlier runs; returning false accepts it.
+	Reject func(value string) bool `json:"-"`
+
+	// OnGenerated, when set, is called with each password as soon as it is
+	// generated, e.g. to store it in a vault. An error aborts generation and is
+	// returned by GeneratePassword. The passwords are then not buffered:
+	// GeneratePassword returns none and GenerateResult.Passwords is empty, so
+	// large counts need no memory for the batch.
+	OnGenerated func(GeneratedPassword) error `json:"-"`
+
+	// SortByEntropy sorts the returned passwords by descending entropy (see
+	// the SortByEntropy function). Passwords of one batch share a
+	// configuration and so an entropy, so this only matters when that changes,
+	// e.g. for results combined across runs. It has nothing to sort when
+	// OnGenerated is set, which sees generation order.
+	SortByEntropy bool `json:"sort_by_entropy"`
+
+	// Workers is the number of goroutines generating passwords concurrently.
+	// 0 selects a count automatically from the batch size and the number of
+	// CPUs (see workerCount); 1 generates sequentially. With more than one
+	// worker, Reject calls are serialized and OnGenerated and OnProgress run on
+	// the calling goroutine, in completion order rather than batch order.
+	// Seeded generation always uses a single worker.
+	Workers int `json:"workers"`
+
+	OnProgress    func(done, total int) `json:"-"` // Optional callback reporting generation progress
+	ProgressEvery int                   `json:"-"` // Invoke OnProgress every N passwords (values < 1 mean every password)
+}
+
+// CharGroups lists groups of characters. It is encoded to JSON as an array of
+// strings, such as ["!@#", "$%^"], rather than arrays of code points.
+type CharGroups [][]rune
+
+// MarshalJSON implements json.Marshaler.
+func (g CharGroups) MarshalJSON() ([]byte, error) {
+	if g == nil {
+		return []byte("null"), nil
+	}
+	groups := make([]string, len(g))
+	for i, group := range g {
+		groups[i] = string(group)
+	}
+	return json.Marshal(groups)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *CharGroups) UnmarshalJSON(data []byte) error {
+	var groups []string
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return err
+	}
+	if groups == nil {
+		*g = nil
+		return nil
+	}
+	*g = make(CharGroups, len(groups))
+	for i, group := range groups {
+		(*g)[i] = []rune(group)
+	}
+	return nil
 }
 
 // GeneratedPassword holds a generated password and its analysis.
@@ -50,6 +321,10 @@ func validateOptions(opt PasswordOptions) error {
 	if opt.UseLower {
 		minLength++
 	}
+	if opt.MinClasses > 0 {
+		minLength = min(minLength, opt.MinClasses)
+	}
+	minLength += len(opt.RequireCategory) + len(opt.RequireFromGroups)
 
 	if opt.Length < minLength {
 		return errors.New("length is too short for the selected character sets")
@@ -57,17 +332,216 @@ func validateOptions(opt PasswordOptions) error {
 	if opt.Count < 1 {
 		return errors.New("count must be greater than 0")
 	}
+	if opt.MaxLength < 0 {
+		return errors.New("maximum length cannot be negative")
+	}
+	if limit := cmp.Or(opt.MaxLength, DefaultMaxLength); opt.Length > limit {
+		return fmt.Errorf("length %d exceeds the maximum of %d", opt.Length, limit)
+	}
 	if !opt.UseUpper && !opt.UseLower && !opt.UseNumbers && !opt.UseSpecialChars {
 		return errors.New("at least one character set must be selected")
 	}
+	if opt.Workers < 0 {
+		return errors.New("workers cannot be negative")
+	}
+	if opt.MinClasses < 0 {
+		return errors.New("minimum character classes cannot be negative")
+	}
+	if n := len(enabledClasses(opt)); opt.MinClasses > n {
+		return fmt.Errorf("minimum character classes (%d) exceeds the %d selected character sets", opt.MinClasses, n)
+	}
+	if opt.SpecialFrequency < 0 || opt.SpecialFrequency > 1 {
+		return errors.New("special frequency must be between 0 and 1")
+	}
+	if opt.UpperRatio < 0 || opt.UpperRatio > 1 {
+		return errors.New("upper ratio must be between 0 and 1")
+	}
+	if opt.UpperRatio > 0 && (!opt.UseUpper || !opt.UseLower) {
+		return errors.New("upper ratio requires both uppercase and lowercase letters")
+	}
+	if opt.NoShift && opt.UseUpper {
+		return errors.New("uppercase letters need Shift; disable them to avoid the shift key")
+	}
+	if opt.CaseInsensitive && opt.UseUpper && opt.UseLower {
+		return errors.New("case-insensitive passwords can use only one letter case; disable uppercase or lowercase letters")
+	}
+	if opt.FullASCIISymbols && !opt.UseSpecialChars {
+		return errors.New("full ASCII symbols require special characters to be enabled")
+	}
+	if opt.AmbiguousChars != "" && !opt.ExcludeAmbiguous {
+		return errors.New("ambiguous characters are only used when excluding them")
+	}
+	if opt.SpecialChars != "" {
+		if !opt.UseSpecialChars {
+			return errors.New("custom special characters require special characters to be enabled")
+		}
+		if opt.FullASCIISymbols {
+			return errors.New("custom special characters cannot be combined with full ASCII symbols")
+		}
+		for _, r := range opt.SpecialChars {
+			if r < ' ' || r > '~' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return errors.New("custom special characters must be printable ASCII punctuation or space")
+			}
+		}
+	}
+	if opt.SpecialFrequency > 0 && !opt.UseSpecialChars {
+		return errors.New("special frequency requires special characters to be enabled")
+	}
+	if opt.MaxUpper < 0 || opt.MaxLower < 0 || opt.MaxNumbers < 0 || opt.MaxSpecial < 0 {
+		return errors.New("maximum character counts cannot be negative")
+	}
+	for _, c := range enabledClasses(opt) {
+		if c.chars == "" {
+			return fmt.Errorf("excluded characters leave no %s characters to choose from", c.class)
+		}
+	}
+	// A set of only whitespace is kept off both edges, so it needs an interior position.
+	for _, c := range enabledClasses(opt) {
+		if strings.TrimFunc(c.chars, unicode.IsSpace) == "" && opt.Length < 3 {
+			return fmt.Errorf("length must be at least 3 to keep the whitespace-only %s set away from both edges", c.class)
+		}
+	}
+	capacity := 0
+	for _, c := range enabledClasses(opt) {
+		if c.max == 0 {
+			capacity = opt.Length
+			break
+		}
+		capacity += c.max
+	}
+	if capacity < opt.Length {
+		return errors.New("maximum character counts leave too few characters for the requested length")
+	}
+	for _, r := range opt.ExtraChars {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return errors.New("extra characters must be printable")
+		}
+	}
+	if opt.BalanceCase {
+		if !opt.UseUpper || !opt.UseLower {
+			return errors.New("balanced case requires both uppercase and lowercase letters")
+		}
+		if opt.CaseTolerance < 0 {
+			return errors.New("case tolerance cannot be negative")
+		}
+		// With letters only, a maximum on one case forces the other to fill the rest.
+		if !opt.UseNumbers && !opt.UseSpecialChars && opt.ExtraChars == "" {
+			for _, m := range []int{opt.MaxUpper, opt.MaxLower} {
+				if m > 0 && opt.Length-2*m > caseTolerance(opt) {
+					return errors.New("maximum letter counts are too low to balance case at this length")
+				}
+			}
+		}
+	}
+	if opt.ScatterDigits && opt.UseNumbers && !opt.UseUpper && !opt.UseLower && !opt.UseSpecialChars && opt.ExtraChars == "" {
+		return errors.New("cannot scatter digits when numbers are the only set selected")
+	}
+	if opt.MinDistinct < 0 {
+		return errors.New("minimum distinct characters cannot be negative")
+	}
+	if opt.MinDistinct > opt.Length {
+		return errors.New("minimum distinct characters cannot exceed the length")
+	}
+	if size := len(newPools(opt).charset); opt.MinDistinct > size {
+		return fmt.Errorf("minimum distinct characters cannot exceed the character set size of %d", size)
+	}
+	if size := len(newPools(opt).charset); opt.NoRepeatedBigrams && opt.Length > maxBigramLength(size) {
+		return fmt.Errorf("a character set of %d is too small for length %d without repeated bigrams; use a length of at most %d", size, opt.Length, maxBigramLength(size))
+	}
+	for i, runes := range newPools(opt).categories {
+		name := opt.RequireCategory[i]
+		if _, ok := unicode.Categories[name]; !ok {
+			return fmt.Errorf("unknown Unicode category %q", name)
+		}
+		if len(runes) == 0 {
+			return fmt.Errorf("extra characters contain no rune in Unicode category %q", name)
+		}
+	}
+	for i, group := range newPools(opt).groups {
+		if len(group) == 0 {
+			return fmt.Errorf("required group %d has no character in the pool", i+1)
+		}
+	}
+	for _, r := range opt.DateToken {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return errors.New("date token must be printable")
+		}
+	}
+	if _, err := ExpandDateToken(opt.DateToken, time.Time{}); err != nil {
+		return err
+	}
+	if !prefixPattern.MatchString(opt.Prefix) {
+		return errors.New("prefix may only contain letters, digits, '-' and '_'")
+	}
+	if !qaMarkerPattern.MatchString(opt.QAMarker) {
+		return errors.New("QA marker may only contain letters and digits")
+	}
+	// The marker is checked with the rest of the password, so it must come
+	// from the same characters and fit within the maximum counts.
+	for _, r := range opt.QAMarker {
+		if !slices.Contains(newPools(opt).charset, r) {
+			return fmt.Errorf("QA marker character %q is not in the character set", r)
+		}
+	}
+	for _, c := range enabledClasses(opt) {
+		if n := countIn(opt.QAMarker, c.chars); c.max > 0 && n > c.max {
+			return fmt.Errorf("QA marker has %d characters from %q, more than the maximum of %d", n, c.chars, c.max)
+		}
+	}
+	if opt.IdentifierSafe {
+		if !opt.UseUpper && !opt.UseLower && !opt.UseSpecialChars {
+			return errors.New("identifier-safe passwords need letters or special characters to start with")
+		}
+		if strings.Contains(opt.Prefix, "-") {
+			return errors.New("identifier-safe passwords cannot have '-' in the prefix")
+		}
+		if opt.Prefix != "" && unicode.IsDigit(rune(opt.Prefix[0])) {
+			return errors.New("identifier-safe passwords cannot have a prefix starting with a digit")
+		}
+		if opt.QAMarker != "" && unicode.IsDigit(rune(opt.QAMarker[0])) {
+			return errors.New("identifier-safe passwords cannot have a QA marker starting with a digit")
+		}
+		if opt.DateToken != "" || opt.RealSeparator != 0 {
+			return errors.New("identifier-safe passwords cannot use a date token or real separator")
+		}
+	}
+	if opt.SeparatorEvery < 0 {
+		return errors.New("separator interval cannot be negative")
+	}
+	if (opt.RealSeparator != 0) != (opt.SeparatorEvery > 0) {
+		return errors.New("a real separator and its interval must be set together")
+	}
+	if opt.RealSeparator != 0 {
+		r := opt.RealSeparator
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return errors.New("real separator must be printable and not whitespace")
+		}
+		if slices.Contains(newPools(opt).charset, r) {
+			return fmt.Errorf("real separator %q is part of the character set", r)
+		}
+		if strings.ContainsRune(excludedChars(opt), r) {
+			return fmt.Errorf("real separator %q is an excluded character", r)
+		}
+		if opt.NoShift && needsShift(r) {
+			return fmt.Errorf("real separator %q needs Shift", r)
+		}
+	}
+	if opt.NoLeadingSpecial || opt.NoTrailingSpecial {
+		if opt.UseSpecialChars && !opt.UseUpper && !opt.UseLower && !opt.UseNumbers {
+			return errors.New("cannot forbid special characters at the edges when special characters are the only set selected")
+		}
+		if opt.UseSpecialChars && opt.NoLeadingSpecial && opt.NoTrailingSpecial && opt.Length < 3 {
+			return errors.New("length must be at least 3 to keep special characters away from both edges")
+		}
+	}
 	return nil
 }
 
-// shuffle randomly shuffles a slice of runes in place using a cryptographically secure random source.
-func shuffle(runes []rune) error {
+// shuffle randomly shuffles a slice of runes in place using random numbers drawn from src.
+func shuffle(src io.Reader, runes []rune) error {
 	N := len(runes)
 	for i := 0; i < N-1; i++ {
-		r, err := secureRandomInt(N - i)
+		r, err := randomInt(src, N-i)
 		if err != nil {
 			return err
 		}
@@ -77,42 +551,309 @@ func shuffle(runes []rune) error {
 	return nil
 }
 
-// buildCharset constructs the character set string based on the provided options.
-func buildCharset(opt PasswordOptions) string {
-	var charset strings.Builder
+// randomChance returns true with probability p, drawing from src.
+func randomChance(src io.Reader, p float64) (bool, error) {
+	const resolution = 1_000_000
+	n, err := randomInt(src, resolution)
+	if err != nil {
+		return false, err
+	}
+	return float64(n) < p*resolution, nil
+}
+
+// enforceEdges ensures that the first and last runes of password satisfy
+// neither the leading nor the trailing rule respectively; a nil rule allows
+// every rune. An offending edge is swapped with a random interior position
+// holding an allowed rune, which preserves the per-class guarantees. If no such
+// position exists, every interior rune is forbidden and the edge is re-rolled
+// from the allowed runes of pool instead.
+func enforceEdges(src io.Reader, password []rune, leading, trailing func(rune) bool, pool []rune) error {
+	last := len(password) - 1
+	type edge struct {
+		index     int
+		forbidden func(rune) bool
+	}
+	var edges []edge
+	if leading != nil {
+		edges = append(edges, edge{0, leading})
+	}
+	if trailing != nil && last > 0 {
+		edges = append(edges, edge{last, trailing})
+	}
+
+	for _, e := range edges {
+		if !e.forbidden(password[e.index]) {
+			continue
+		}
+		var candidates []int
+		for i, r := range password {
+			if e.forbidden(r) || (leading != nil && i == 0) || (trailing != nil && i == last) {
+				continue
+			}
+			candidates = append(candidates, i)
+		}
+		if len(candidates) > 0 {
+			n, err := randomInt(src, len(candidates))
+			if err != nil {
+				return err
+			}
+			c := candidates[n]
+			password[e.index], password[c] = password[c], password[e.index]
+			continue
+		}
+		var allowed []rune
+		for _, r := range pool {
+			if !e.forbidden(r) {
+				allowed = append(allowed, r)
+			}
+		}
+		if len(allowed) == 0 {
+			return errors.New("no character is allowed at the edges of the password")
+		}
+		n, err := randomInt(src, len(allowed))
+		if err != nil {
+			return err
+		}
+		password[e.index] = allowed[n]
+	}
+	return nil
+}
+
+// edgeRule returns the rule for one edge of a password: whitespace is always
+// forbidden, and special characters are too when noSpecial is set.
+func edgeRule(opt PasswordOptions, noSpecial bool) func(rune) bool {
+	if !noSpecial {
+		return unicode.IsSpace
+	}
+	special := specialSet(opt)
+	return func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(special, r)
+	}
+}
+
+// isSpecial reports whether r is one of the special characters.
+func isSpecial(r rune) bool {
+	return strings.ContainsRune(specialChars, r)
+}
+
+// SpecialSet returns the special characters generation draws from for opt,
+// after custom sets, exclusions and modes such as NoShift are applied. It
+// ignores whether opt.UseSpecialChars is set.
+func SpecialSet(opt PasswordOptions) string {
+	return specialSet(opt)
+}
+
+// specialSet returns the special characters selected by opt, without the
+// excluded ones.
+func specialSet(opt PasswordOptions) string {
+	if opt.IdentifierSafe {
+		return withoutExcluded(opt, identifierSymbol)
+	}
+	if opt.SpecialChars != "" {
+		return withoutExcluded(opt, customSpecialChars(opt))
+	}
+	if opt.NoShift {
+		return withoutExcluded(opt, unshiftedSymbols)
+	}
+	if opt.FullASCIISymbols {
+		return withoutExcluded(opt, fullASCIISymbols)
+	}
+	return withoutExcluded(opt, specialChars)
+}
+
+// customSpecialChars returns opt.SpecialChars without duplicates and, with
+// opt.NoShift, without the characters that need Shift.
+func customSpecialChars(opt PasswordOptions) string {
+	var b strings.Builder
+	for _, r := range opt.SpecialChars {
+		if !strings.ContainsRune(b.String(), r) && !(opt.NoShift && needsShift(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// excludedChars returns the characters opt removes from every character set.
+func excludedChars(opt PasswordOptions) string {
+	excluded := opt.AvoidOldChars
+	if opt.XMLSafe {
+		excluded += xmlUnsafeChars
+	}
+	if opt.ExcludeAmbiguous {
+		excluded += cmp.Or(opt.AmbiguousChars, defaultAmbiguousChars)
+	}
+	return excluded
+}
+
+// withoutExcluded returns set without the characters excluded by opt.
+func withoutExcluded(opt PasswordOptions, set string) string {
+	excluded := excludedChars(opt)
+	if excluded == "" {
+		return set
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+		return r
+	}, set)
+}
+
+// classSet is an enabled character set together with its maximum count.
+type classSet struct {
+	class CharClass // Class the set belongs to
+	chars string    // Characters in the set, without excluded ones
+	max   int       // Maximum occurrences in a password (0 means unlimited)
+}
+
+// enabledClasses returns the character sets selected in opt, in generation order.
+func enabledClasses(opt PasswordOptions) []classSet {
+	var classes []classSet
 	if opt.UseUpper {
-		charset.WriteString(uppercase)
+		classes = append(classes, classSet{ClassUpper, withoutExcluded(opt, uppercase), opt.MaxUpper})
 	}
 	if opt.UseLower {
-		charset.WriteString(lowercase)
+		classes = append(classes, classSet{ClassLower, withoutExcluded(opt, lowercase), opt.MaxLower})
 	}
 	if opt.UseNumbers {
-		charset.WriteString(numbers)
+		classes = append(classes, classSet{ClassNumbers, withoutExcluded(opt, numbers), opt.MaxNumbers})
 	}
 	if opt.UseSpecialChars {
-		charset.WriteString(specialChars)
+		classes = append(classes, classSet{ClassSpecial, specialSet(opt), opt.MaxSpecial})
+	}
+	return classes
+}
+
+// countIn returns how many runes of s are contained in set.
+func countIn(s, set string) int {
+	n := 0
+	for _, r := range s {
+		if strings.ContainsRune(set, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// enforceMaxima re-rolls fill positions (from index from onwards) whose
+// character set already exceeds its maximum, drawing each replacement only
+// from the sets that still have spare capacity.
+func enforceMaxima(src io.Reader, opt PasswordOptions, password []rune, from int) error {
+	classes := enabledClasses(opt)
+	counts := make([]int, len(classes))
+	classOf := func(r rune) int {
+		for i, c := range classes {
+			if strings.ContainsRune(c.chars, r) {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, r := range password {
+		if i := classOf(r); i >= 0 {
+			counts[i]++
+		}
+	}
+
+	for j := from; j < len(password); j++ {
+		i := classOf(password[j])
+		if i < 0 || classes[i].max == 0 || counts[i] <= classes[i].max {
+			continue
+		}
+		var pool []rune
+		for k, c := range classes {
+			if c.max == 0 || counts[k] < c.max {
+				pool = append(pool, []rune(c.chars)...)
+			}
+		}
+		n, err := randomInt(src, len(pool))
+		if err != nil {
+			return err
+		}
+		password[j] = pool[n]
+		counts[i]--
+		counts[classOf(pool[n])]++
+	}
+	return nil
+}
+
+// buildCharset constructs the character set string based on the provided options.
+func buildCharset(opt PasswordOptions) string {
+	var charset strings.Builder
+	for _, c := range enabledClasses(opt) {
+		charset.WriteString(c.chars)
 	}
 	return charset.String()
 }
 
 // secureRandomInt returns a cryptographically secure random integer in [0, max).
 func secureRandomInt(max int) (int, error) {
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	return randomInt(rand.Reader, max)
+}
+
+// randomInt returns a uniformly distributed random integer in [0, max), drawing from src.
+// Draws from crypto/rand use the faster secureIndex. Deterministic sources,
+// such as seeded and site generation, keep the rand.Int sampling they have
+// always used, since changing how bytes map to indexes would change every
+// reproducible password.
+func randomInt(src io.Reader, max int) (int, error) {
+	if src == rand.Reader {
+		n, err := secureIndex(src, max)
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate random number: %w", err)
+		}
+		return n, nil
+	}
+	n, err := rand.Int(src, big.NewInt(int64(max)))
 	if err != nil {
 		return 0, fmt.Errorf("failed to generate random number: %w", err)
 	}
 	return int(n.Int64()), nil
 }
 
+// secureIndex returns a uniformly distributed integer in [0, n) using rejection
+// sampling on 64-bit words read from src. Words below 2^64 mod n are rejected so
+// every residue is equally likely; this avoids the big.Int allocations of
+// rand.Int, which dominate the cost of drawing small indexes.
+func secureIndex(src io.Reader, n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid range %d", n)
+	}
+	bound := uint64(n)
+	threshold := -bound % bound
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(src, buf[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.LittleEndian.Uint64(buf[:]); v >= threshold {
+			return int(v % bound), nil
+		}
+	}
+}
+
 // PasswordEntropy calculates the entropy of a password and returns
 // (entropy, strength label, error).
-// Strength is classified as "Excellent", "Strong", "Moderate", or "Weak".
+// Strength is classified as "Excellent", "Strong", "Moderate", or "Weak" unless a
+// custom classifier is installed with SetClassifier.
 func PasswordEntropy(password string) (float64, string, error) {
 	if len(password) == 0 {
 		return 0, "", errors.New("password is empty")
 	}
 
-	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	charsetSize := detectCharsetSize(password)
+	if charsetSize == 0 {
+		return 0, "", errors.New("password contains no recognized character types")
+	}
+
+	entropy := float64(len([]rune(password))) * math.Log2(float64(charsetSize))
+	return entropy, classifyEntropy(entropy), nil
+}
+
+// detectCharsetSize returns the combined size of every character class that
+// appears in password. Characters outside the known classes are ignored.
+func detectCharsetSize(password string) int {
+	var hasUpper, hasLower, hasNumber, hasSpecial, hasFullSymbol bool
 
 	for _, r := range password {
 		switch {
@@ -124,119 +865,515 @@ func PasswordEntropy(password string) (float64, string, error) {
 			hasNumber = true
 		case strings.ContainsRune(specialChars, r):
 			hasSpecial = true
+		case strings.ContainsRune(fullASCIISymbols, r):
+			hasFullSymbol = true
 		}
 	}
 
-	charsetSize := 0
+	size := 0
 	if hasUpper {
-		charsetSize += len(uppercase)
+		size += len(uppercase)
 	}
 	if hasLower {
-		charsetSize += len(lowercase)
+		size += len(lowercase)
 	}
 	if hasNumber {
-		charsetSize += len(numbers)
-	}
-	if hasSpecial {
-		charsetSize += len(specialChars)
+		size += len(numbers)
 	}
-	if charsetSize == 0 {
-		return 0, "", errors.New("password contains no recognized character types")
+	// A symbol outside the curated set means the password was drawn from all
+	// printable ASCII punctuation.
+	switch {
+	case hasFullSymbol:
+		size += len(fullASCIISymbols)
+	case hasSpecial:
+		size += len(specialChars)
 	}
+	return size
+}
 
-	entropy := float64(len([]rune(password))) * math.Log2(float64(charsetSize))
-
-	var strength string
+// strengthLabel maps an entropy value in bits to its strength label.
+func strengthLabel(entropy float64) string {
 	switch {
 	case entropy >= 80:
-		strength = "Excellent"
+		return "Excellent"
 	case entropy >= 60:
-		strength = "Strong"
+		return "Strong"
 	case entropy >= 40:
-		strength = "Moderate"
+		return "Moderate"
 	default:
-		strength = "Weak"
+		return "Weak"
 	}
+}
 
-	return entropy, strength, nil
+// drawFill draws a single fill character. By default it draws uniformly from the
+// full charset; when opt.SpecialFrequency is set it first decides whether the
+// position is special, then draws uniformly from the chosen pool. When
+// opt.UpperRatio is set and the character is a letter, it is redrawn from the
+// uppercase or lowercase pool chosen with that probability.
+func drawFill(src io.Reader, opt PasswordOptions, p pools) (rune, error) {
+	pool := p.charset
+	if opt.SpecialFrequency > 0 && len(p.nonSpecial) > 0 {
+		special, err := randomChance(src, opt.SpecialFrequency)
+		if err != nil {
+			return 0, err
+		}
+		pool = p.nonSpecial
+		if special {
+			pool = []rune(specialSet(opt))
+		}
+	}
+	n, err := randomInt(src, len(pool))
+	if err != nil {
+		return 0, err
+	}
+	r := pool[n]
+	if opt.UpperRatio > 0 && (slices.Contains(p.upper, r) || slices.Contains(p.lower, r)) {
+		upper, err := randomChance(src, opt.UpperRatio)
+		if err != nil {
+			return 0, err
+		}
+		pool = p.lower
+		if upper {
+			pool = p.upper
+		}
+		if n, err = randomInt(src, len(pool)); err != nil {
+			return 0, err
+		}
+		r = pool[n]
+	}
+	return r, nil
 }
 
-// GeneratePassword generates one or more passwords based on the provided options.
-// Each password is guaranteed to contain at least one character from each selected set.
-// Returns a slice of GeneratedPassword, or an error if options are invalid.
-func GeneratePassword(opt PasswordOptions) ([]GeneratedPassword, error) {
-	if err := validateOptions(opt); err != nil {
-		return nil, err
+// reportProgress invokes opt.OnProgress when done is a multiple of
+// opt.ProgressEvery or when the batch is complete.
+func reportProgress(opt PasswordOptions, done int) {
+	if opt.OnProgress == nil {
+		return
+	}
+	every := max(opt.ProgressEvery, 1)
+	if done%every == 0 || done == opt.Count {
+		opt.OnProgress(done, opt.Count)
 	}
+}
 
-	charset := buildCharset(opt)
-	charsetRunes := []rune(charset)
-	passwords := make([]GeneratedPassword, opt.Count)
+// maxAttempts bounds how many candidates are generated for a single password
+// before giving up on options whose constraints cannot be satisfied.
+const maxAttempts = 10000
 
-	for i := range passwords {
-		password := make([]rune, opt.Length)
-		position := 0
+// digitTailLength is the number of trailing characters that may not all be
+// digits when PasswordOptions.ScatterDigits is set.
+const digitTailLength = 2
 
-		// Ensure at least one character from each selected set
-		if opt.UseUpper {
-			n, err := secureRandomInt(len(uppercase))
-			if err != nil {
-				return nil, err
-			}
-			password[position] = rune(uppercase[n])
-			position++
-		}
-		if opt.UseLower {
-			n, err := secureRandomInt(len(lowercase))
-			if err != nil {
-				return nil, err
+// pools holds the rune pools derived from a set of options.
+type pools struct {
+	charset    []rune   // All enabled characters
+	nonSpecial []rune   // Enabled characters excluding special characters
+	categories [][]rune // Extra runes in each of opt.RequireCategory, in order
+	groups     [][]rune // Pool runes in each of opt.RequireFromGroups, in order
+	upper      []rune   // Enabled uppercase letters, set only with opt.UpperRatio
+	lower      []rune   // Enabled lowercase letters, set only with opt.UpperRatio
+}
+
+// newPools builds the rune pools for opt.
+func newPools(opt PasswordOptions) pools {
+	nonSpecialOpt := opt
+	nonSpecialOpt.UseSpecialChars = false
+	p := pools{
+		charset:    []rune(buildCharset(opt)),
+		nonSpecial: []rune(buildCharset(nonSpecialOpt)),
+	}
+
+	extra := extraRunes(opt, p.charset)
+	p.charset = append(p.charset, extra...)
+	p.nonSpecial = append(p.nonSpecial, extra...)
+	if opt.UpperRatio > 0 {
+		caseOpt := opt
+		caseOpt.UseLower, caseOpt.UseNumbers, caseOpt.UseSpecialChars = false, false, false
+		p.upper = []rune(buildCharset(caseOpt))
+		caseOpt.UseUpper, caseOpt.UseLower = false, true
+		p.lower = []rune(buildCharset(caseOpt))
+	}
+	for _, name := range opt.RequireCategory {
+		var runes []rune
+		if table := unicode.Categories[name]; table != nil {
+			for _, r := range extra {
+				if unicode.Is(table, r) {
+					runes = append(runes, r)
+				}
 			}
-			password[position] = rune(lowercase[n])
-			position++
 		}
-		if opt.UseNumbers {
-			n, err := secureRandomInt(len(numbers))
-			if err != nil {
-				return nil, err
+		p.categories = append(p.categories, runes)
+	}
+	for _, group := range opt.RequireFromGroups {
+		var runes []rune
+		for _, r := range group {
+			if slices.Contains(p.charset, r) && !slices.Contains(runes, r) {
+				runes = append(runes, r)
 			}
-			password[position] = rune(numbers[n])
-			position++
 		}
-		if opt.UseSpecialChars {
-			n, err := secureRandomInt(len(specialChars))
-			if err != nil {
-				return nil, err
-			}
-			password[position] = rune(specialChars[n])
-			position++
+		p.groups = append(p.groups, runes)
+	}
+	return p
+}
+
+// extraRunes returns the runes of opt.ExtraChars that are not already part of
+// base, without duplicates, comparing case-insensitively with
+// opt.CaseInsensitive. With opt.ExcludeHomoglyphs, runes confusable with
+// another rune of the pool are dropped as well. With opt.IdentifierSafe there
+// are none, since every identifier character is already in the pool.
+func extraRunes(opt PasswordOptions, base []rune) []rune {
+	if opt.IdentifierSafe {
+		return nil
+	}
+	key := func(r rune) rune { return r }
+	if opt.CaseInsensitive {
+		key = unicode.ToLower
+	}
+	present := make(map[rune]bool, len(base))
+	for _, r := range base {
+		present[key(r)] = true
+	}
+	var extra []rune
+	excluded := excludedChars(opt)
+	for _, r := range opt.ExtraChars {
+		if !present[key(r)] && !strings.ContainsRune(excluded, r) && !(opt.NoShift && needsShift(r)) {
+			present[key(r)] = true
+			extra = append(extra, r)
 		}
+	}
+	if opt.ExcludeHomoglyphs {
+		extra = excludeHomoglyphs(base, extra)
+	}
+	return extra
+}
 
-		// Fill the rest of the password with random characters from the charset
-		for j := position; j < opt.Length; j++ {
-			n, err := secureRandomInt(len(charsetRunes))
-			if err != nil {
-				return nil, err
-			}
-			password[j] = charsetRunes[n]
+// poolEntropy returns the entropy in bits of n characters drawn uniformly from the pool.
+func poolEntropy(n int, pool []rune) float64 {
+	return float64(n) * math.Log2(float64(len(pool)))
+}
+
+// resolveOptions applies the configured policies to opt and validates the result.
+func resolveOptions(opt PasswordOptions) (PasswordOptions, error) {
+	opt, err := applyPolicies(opt)
+	if err != nil {
+		return opt, err
+	}
+	return opt, validateOptions(opt)
+}
+
+// generateCandidate builds a single random password for opt. Each required set
+// (see requiredClasses) contributes at least one character; the remaining
+// positions are filled from the whole charset and the result is shuffled.
+func generateCandidate(src io.Reader, opt PasswordOptions, p pools) ([]rune, error) {
+	password := make([]rune, opt.Length)
+	position := 0
+
+	classes, err := requiredClasses(src, opt)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure at least one character from each required set
+	for _, c := range classes {
+		n, err := randomInt(src, len(c.chars))
+		if err != nil {
+			return nil, err
 		}
+		password[position] = rune(c.chars[n])
+		position++
+	}
+	// Ensure at least one rune from each required Unicode category and group
+	for _, runes := range slices.Concat(p.categories, p.groups) {
+		n, err := randomInt(src, len(runes))
+		if err != nil {
+			return nil, err
+		}
+		password[position] = runes[n]
+		position++
+	}
 
-		// Shuffle to avoid predictable character positions
-		if err := shuffle(password); err != nil {
+	// Fill the rest of the password with random characters from the charset
+	for j := position; j < opt.Length; j++ {
+		r, err := drawFill(src, opt, p)
+		if err != nil {
 			return nil, err
 		}
+		password[j] = r
+	}
+
+	if err := enforceMaxima(src, opt, password, position); err != nil {
+		return nil, err
+	}
+
+	// Shuffle to avoid predictable character positions
+	if err := shuffle(src, password); err != nil {
+		return nil, err
+	}
+	leading, trailing := edgeRule(opt, opt.NoLeadingSpecial), edgeRule(opt, opt.NoTrailingSpecial)
+	if opt.IdentifierSafe {
+		rule := leading
+		leading = func(r rune) bool { return rule(r) || unicode.IsDigit(r) }
+	}
+	if err := enforceEdges(src, password, leading, trailing, p.charset); err != nil {
+		return nil, err
+	}
+	return password, nil
+}
 
-		pwdStr := string(password)
-		entropy, strength, err := PasswordEntropy(pwdStr)
+// requiredClasses returns the character sets that must each contribute a
+// character: every selected set, or opt.MinClasses of them chosen at random
+// from src when it is positive.
+func requiredClasses(src io.Reader, opt PasswordOptions) ([]classSet, error) {
+	classes := enabledClasses(opt)
+	if opt.MinClasses <= 0 {
+		return classes, nil
+	}
+	for i := range opt.MinClasses {
+		n, err := randomInt(src, len(classes)-i)
 		if err != nil {
 			return nil, err
 		}
+		classes[i], classes[i+n] = classes[i+n], classes[i]
+	}
+	return classes[:opt.MinClasses], nil
+}
+
+// caseTolerance returns the effective maximum difference between the uppercase
+// and lowercase counts when opt.BalanceCase is set.
+func caseTolerance(opt PasswordOptions) int {
+	return max(opt.CaseTolerance, 1)
+}
+
+// hasDigitTail reports whether the last digitTailLength runes of password are
+// all digits.
+func hasDigitTail(password string) bool {
+	runes := []rune(password)
+	if len(runes) < digitTailLength {
+		return false
+	}
+	for _, r := range runes[len(runes)-digitTailLength:] {
+		if !strings.ContainsRune(numbers, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// distinctRunes returns the number of distinct runes in s.
+func distinctRunes(s string) int {
+	seen := make(map[rune]struct{})
+	for _, r := range s {
+		seen[r] = struct{}{}
+	}
+	return len(seen)
+}
+
+// checkCandidate returns an error describing why password does not satisfy the
+// constraints in opt that cannot be guaranteed up front, or nil if it does.
+func checkCandidate(opt PasswordOptions, password string) error {
+	for _, c := range enabledClasses(opt) {
+		if n := countIn(password, c.chars); c.max > 0 && n > c.max {
+			return fmt.Errorf("password contains %d characters from %q, more than the maximum of %d", n, c.chars, c.max)
+		}
+	}
+	if opt.BalanceCase {
+		upper, lower := countIn(password, uppercase), countIn(password, lowercase)
+		if diff := upper - lower; diff > caseTolerance(opt) || -diff > caseTolerance(opt) {
+			return fmt.Errorf("password has %d uppercase and %d lowercase letters, more than %d apart", upper, lower, caseTolerance(opt))
+		}
+	}
+	if opt.ScatterDigits && hasDigitTail(password) {
+		return errors.New("password ends in a run of digits")
+	}
+	if opt.MinDistinct > 0 {
+		if n := distinctRunes(password); n < opt.MinDistinct {
+			return fmt.Errorf("password has %d distinct characters, fewer than the minimum of %d", n, opt.MinDistinct)
+		}
+	}
+	if opt.AvoidKeyboardAdjacent {
+		if a, b, ok := adjacentKeyPair(password); ok {
+			return fmt.Errorf("password contains adjacent keys %q", string([]rune{a, b}))
+		}
+	}
+	if opt.NoSequential {
+		if run := sequentialRun(password); run != "" {
+			return fmt.Errorf("password contains the sequential run %q", run)
+		}
+	}
+	if opt.NoRepeatedBigrams {
+		if bigram := repeatedBigram(password); bigram != "" {
+			return fmt.Errorf("password repeats the bigram %q", bigram)
+		}
+	}
+	for _, p := range opt.Policies {
+		if violations := p.CheckWithSpecials(password, specialSet(opt)); len(violations) > 0 {
+			return &PolicyError{Policy: p.Name, Violations: violations}
+		}
+	}
+	return nil
+}
+
+// insertMarker returns password with marker inserted at a random position
+// drawn from src. An empty marker leaves password unchanged.
+func insertMarker(src io.Reader, password []rune, marker string) (string, error) {
+	if marker == "" {
+		return string(password), nil
+	}
+	n, err := randomInt(src, len(password)+1)
+	if err != nil {
+		return "", err
+	}
+	return string(password[:n]) + marker + string(password[n:]), nil
+}
+
+// generateValue generates candidates until one satisfies opt and returns it
+// wrapped in head and tail, along with the number of discarded candidates.
+func generateValue(src io.Reader, opt PasswordOptions, p pools, head, tail string) (string, int, error) {
+	for attempt := 0; ; attempt++ {
+		password, err := generateCandidate(src, opt, p)
+		if err != nil {
+			return "", attempt, err
+		}
+		marked, err := insertMarker(src, password, opt.QAMarker)
+		if err != nil {
+			return "", attempt, err
+		}
+		body := marked
+		if opt.RealSeparator != 0 {
+			body = FormatGrouped(body, opt.SeparatorEvery, string(opt.RealSeparator))
+		}
+		value := head + body + tail
+		err = checkCandidate(opt, marked)
+		if err == nil && opt.Reject != nil && opt.Reject(value) {
+			err = errors.New("candidate was rejected")
+		}
+		if err == nil {
+			return value, attempt, nil
+		}
+		if attempt+1 == maxAttempts {
+			return "", maxAttempts, fmt.Errorf("could not satisfy the constraints after %d attempts: %w", maxAttempts, err)
+		}
+	}
+}
 
-		passwords[i] = GeneratedPassword{
-			Value:    pwdStr,
+// GenerateResult holds the passwords of a generation run along with details
+// about how the run went.
+type GenerateResult struct {
+	Passwords []GeneratedPassword // The generated passwords; empty when OnGenerated is set
+
+	// Retries is the number of candidates discarded and regenerated across the
+	// batch because they violated a constraint. A high value relative to the
+	// number of passwords signals over-constrained options.
+	Retries int
+
+	// Diagnostics lists warnings about the run, such as weak entropy or many
+	// retries, for tools that consume them. It is nil when there are none.
+	Diagnostics []Diagnostic
+}
+
+// ValidateOptions returns the error GeneratePassword would return for opt
+// before generating anything, after widening the options to meet its
+// policies, or nil if the options are valid. Constraints checked per
+// candidate may still fail to be met during generation.
+func ValidateOptions(opt PasswordOptions) error {
+	_, err := resolveOptions(opt)
+	return err
+}
+
+// GeneratePassword generates one or more passwords based on the provided options.
+// Each password is guaranteed to contain at least one character from each selected set.
+// When opt.Policies is set, the options are first widened to meet every policy and
+// candidates that still violate one are regenerated.
+// Returns a slice of GeneratedPassword, or an error if options are invalid.
+//
+// GeneratePassword is safe for concurrent use by any number of goroutines, for
+// services that generate on every request. The package keeps no mutable state
+// shared between calls: the only globals written after initialization are the
+// strength classifier, which SetClassifier guards with a lock, and the cost
+// calibration behind EstimateDuration, which runs once. Every call builds its
+// own pools and random source, so a failed call, even one that exhausted its
+// retries, leaves nothing behind and can simply be retried.
+//
+// opt is only read, so concurrent calls may share the slices in it (Policies,
+// RequireCategory, RequireFromGroups and Seed), but the caller must not modify
+// them while a call runs. Reject may be called from worker goroutines: calls
+// within one generation are serialized, but a Reject shared between concurrent
+// generations must synchronize itself. OnGenerated and OnProgress are called
+// from the calling goroutine.
+func GeneratePassword(opt PasswordOptions) ([]GeneratedPassword, error) {
+	result, err := GenerateWithResult(opt)
+	if err != nil {
+		return nil, err
+	}
+	return result.Passwords, nil
+}
+
+// GenerateWithResult generates passwords like GeneratePassword and also reports
+// how many candidates had to be regenerated.
+func GenerateWithResult(opt PasswordOptions) (GenerateResult, error) {
+	opt, err := resolveOptions(opt)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	token, err := ExpandDateToken(opt.DateToken, now())
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	head, tail := opt.Prefix+token, ""
+	if opt.DateTokenAppend {
+		head, tail = opt.Prefix, token
+	}
+
+	p := newPools(opt)
+	entropy := poolEntropy(opt.Length, p.charset)
+	strength := classifyEntropy(entropy)
+	var result GenerateResult
+	// Passwords handed to OnGenerated are not kept.
+	if opt.OnGenerated == nil {
+		result.Passwords = make([]GeneratedPassword, opt.Count)
+	}
+
+	done := 0
+	emit := func(i int, value string) error {
+		gp := GeneratedPassword{
+			Value:    value,
 			Strength: strength,
 			Entropy:  entropy,
 		}
+		if opt.OnGenerated != nil {
+			if err := opt.OnGenerated(gp); err != nil {
+				return fmt.Errorf("password %d: %w", i+1, err)
+			}
+		} else {
+			result.Passwords[i] = gp
+		}
+		done++
+		reportProgress(opt, done)
+		return nil
 	}
 
-	return passwords, nil
+	if workers := workerCount(opt); workers > 1 {
+		result.Retries, err = generateConcurrently(opt, p, head, tail, workers, emit)
+		if err != nil {
+			return GenerateResult{}, err
+		}
+	} else {
+		src := randomSource(opt)
+		for i := range opt.Count {
+			value, retries, err := generateValue(src, opt, p, head, tail)
+			result.Retries += retries
+			if err != nil {
+				return GenerateResult{}, err
+			}
+			if err := emit(i, value); err != nil {
+				return GenerateResult{}, err
+			}
+		}
+	}
+
+	if opt.SortByEntropy {
+		SortByEntropy(result.Passwords)
+	}
+	result.Diagnostics = diagnose(opt, entropy, result.Retries)
+	return result, nil
 }
@@ -2,11 +2,22 @@
 package generator
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"unicode"
 )
 
+// update rewrites golden files under testdata instead of comparing against them.
+var update = flag.Bool("update", false, "update golden files")
+
 // containsAny returns true if any rune in s is present in set.
 func containsAny(s string, set string) bool {
 	for _, r := range s {
@@ -194,3 +205,1009 @@ func TestGeneratePassword_Uniqueness(t *testing.T) {
 		seen[pwd] = struct{}{}
 	}
 }
+
+// TestGeneratePassword_Progress checks that the progress callback is invoked once per
+// interval plus once for a final partial interval, ending at the total count.
+func TestGeneratePassword_Progress(t *testing.T) {
+	tests := []struct {
+		count, every, wantCalls int
+	}{
+		{count: 100, every: 10, wantCalls: 10},
+		{count: 105, every: 10, wantCalls: 11},
+		{count: 5, every: 0, wantCalls: 5},
+		{count: 3, every: 10, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		var calls, last int
+		opt := PasswordOptions{
+			Length:        8,
+			UseLower:      true,
+			Count:         tt.count,
+			ProgressEvery: tt.every,
+			OnProgress: func(done, total int) {
+				calls++
+				last = done
+				if total != tt.count {
+					t.Errorf("expected total %d, got %d", tt.count, total)
+				}
+			},
+		}
+		if _, err := GeneratePassword(opt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("count=%d every=%d: expected %d progress calls, got %d", tt.count, tt.every, tt.wantCalls, calls)
+		}
+		if last != tt.count {
+			t.Errorf("expected final progress %d, got %d", tt.count, last)
+		}
+	}
+}
+
+// TestGeneratePassword_SpecialFrequency checks over a large sample that the share of
+// special characters approximates the configured frequency.
+func TestGeneratePassword_SpecialFrequency(t *testing.T) {
+	opt := PasswordOptions{
+		Length:           100,
+		UseSpecialChars:  true,
+		UseNumbers:       true,
+		UseUpper:         true,
+		UseLower:         true,
+		Count:            100,
+		SpecialFrequency: 0.1,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var special, total int
+	for _, gp := range passwords {
+		for _, r := range gp.Value {
+			total++
+			if strings.ContainsRune(specialChars, r) {
+				special++
+			}
+		}
+	}
+	// One forced special character per password plus 10% of the 96 fill positions.
+	want := (1 + 0.1*96) / 100
+	got := float64(special) / float64(total)
+	if got < want-0.02 || got > want+0.02 {
+		t.Errorf("expected special frequency around %.3f, got %.3f", want, got)
+	}
+
+	opt.SpecialFrequency = 1.5
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for special frequency above 1")
+	}
+}
+
+// TestGeneratePassword_NoEdgeSpecial checks that the first and last runes are never
+// special characters when the edge constraints are enabled.
+func TestGeneratePassword_NoEdgeSpecial(t *testing.T) {
+	opt := PasswordOptions{
+		Length:            6,
+		UseSpecialChars:   true,
+		UseNumbers:        true,
+		Count:             200,
+		SpecialFrequency:  0.9,
+		NoLeadingSpecial:  true,
+		NoTrailingSpecial: true,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		runes := []rune(gp.Value)
+		if isSpecial(runes[0]) || isSpecial(runes[len(runes)-1]) {
+			t.Errorf("password has a special character at an edge: %s", gp.Value)
+		}
+		if !containsAny(gp.Value, specialChars) || !containsAny(gp.Value, numbers) {
+			t.Errorf("password lost a required character set: %s", gp.Value)
+		}
+	}
+
+	opt = PasswordOptions{Length: 8, UseSpecialChars: true, Count: 1, NoTrailingSpecial: true}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when special characters are the only set selected")
+	}
+}
+
+// TestGeneratePassword_MaxSpecial checks that the number of special characters never
+// exceeds the configured maximum, even when they are heavily weighted.
+func TestGeneratePassword_MaxSpecial(t *testing.T) {
+	opt := PasswordOptions{
+		Length:           16,
+		UseSpecialChars:  true,
+		UseNumbers:       true,
+		UseUpper:         true,
+		UseLower:         true,
+		Count:            200,
+		SpecialFrequency: 0.8,
+		MaxSpecial:       2,
+		MaxNumbers:       3,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if n := countIn(gp.Value, specialChars); n < 1 || n > 2 {
+			t.Errorf("expected 1-2 special characters, got %d in %s", n, gp.Value)
+		}
+		if n := countIn(gp.Value, numbers); n < 1 || n > 3 {
+			t.Errorf("expected 1-3 digits, got %d in %s", n, gp.Value)
+		}
+	}
+
+	opt = PasswordOptions{Length: 10, UseNumbers: true, UseSpecialChars: true, Count: 1, MaxNumbers: 4, MaxSpecial: 4}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when maxima cannot fill the length")
+	}
+}
+
+// TestGeneratePassword_Prefix checks that every password starts with the prefix and
+// that the random part still meets the requested length and character sets.
+func TestGeneratePassword_Prefix(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          10,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           10,
+		Prefix:          "aws-",
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		random, ok := strings.CutPrefix(gp.Value, "aws-")
+		if !ok {
+			t.Fatalf("password %s does not start with the prefix", gp.Value)
+		}
+		if len([]rune(random)) != 10 {
+			t.Errorf("expected random part of length 10, got %d", len([]rune(random)))
+		}
+		if entropy, _, _ := PasswordEntropy(random); entropy != gp.Entropy {
+			t.Errorf("expected entropy of the random part %.2f, got %.2f", entropy, gp.Entropy)
+		}
+	}
+
+	opt.Prefix = "bad prefix!"
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for prefix with unsafe characters")
+	}
+}
+
+// entropyGolden is a pinned PasswordEntropy result stored in testdata.
+type entropyGolden struct {
+	Password string  `json:"password"`
+	Entropy  float64 `json:"entropy"`
+	Strength string  `json:"strength"`
+}
+
+// TestPasswordEntropy_Golden checks that PasswordEntropy values for a fixed set of
+// passwords spanning every strength band do not drift. Run with -update to
+// regenerate testdata/entropy_golden.json after an intentional change.
+func TestPasswordEntropy_Golden(t *testing.T) {
+	path := filepath.Join("testdata", "entropy_golden.json")
+	passwords := []string{
+		"abc",                   // Weak
+		"hunter2",               // Weak
+		"Password1",             // Moderate
+		"correcthorse",          // Moderate
+		"Tr0ub4dor&3",           // Strong
+		"aaaaaaaaaaaaaa",        // Strong
+		"x8#Kq2!vLm9@zP4$",      // Excellent
+		"correcthorsebattery9s", // Excellent
+		"pässwörd",              // non-ASCII runes count toward length only
+	}
+
+	got := make([]entropyGolden, len(passwords))
+	for i, pwd := range passwords {
+		entropy, strength, err := PasswordEntropy(pwd)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", pwd, err)
+		}
+		got[i] = entropyGolden{Password: pwd, Entropy: entropy, Strength: strength}
+	}
+
+	if *update {
+		var buf strings.Builder
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(buf.String()), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var want []entropyGolden
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(want) != len(got) {
+		t.Fatalf("expected %d golden entries, got %d", len(got), len(want))
+	}
+
+	bands := make(map[string]bool)
+	for i, w := range want {
+		g := got[i]
+		if g.Password != w.Password {
+			t.Fatalf("golden entry %d is for %q, expected %q", i, w.Password, g.Password)
+		}
+		if math.Abs(g.Entropy-w.Entropy) > 1e-9 {
+			t.Errorf("entropy for %q drifted: got %.10f, want %.10f", w.Password, g.Entropy, w.Entropy)
+		}
+		if g.Strength != w.Strength {
+			t.Errorf("strength for %q drifted: got %s, want %s", w.Password, g.Strength, w.Strength)
+		}
+		bands[w.Strength] = true
+	}
+	for _, band := range []string{"Weak", "Moderate", "Strong", "Excellent"} {
+		if !bands[band] {
+			t.Errorf("golden set has no password in the %s band", band)
+		}
+	}
+}
+
+// TestGeneratePassword_MinDistinct checks that every password has at least
+// MinDistinct unique runes and that infeasible minimums are rejected.
+func TestGeneratePassword_MinDistinct(t *testing.T) {
+	opt := PasswordOptions{Length: 10, UseNumbers: true, Count: 100, MinDistinct: 8}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		seen := make(map[rune]bool)
+		for _, r := range gp.Value {
+			seen[r] = true
+		}
+		if len(seen) < 8 {
+			t.Errorf("password %s has only %d distinct characters", gp.Value, len(seen))
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordOptions{Length: 6, UseLower: true, Count: 1, MinDistinct: 7}); err == nil {
+		t.Error("expected error when MinDistinct exceeds the length")
+	}
+	if _, err := GeneratePassword(PasswordOptions{Length: 12, UseNumbers: true, Count: 1, MinDistinct: 11}); err == nil {
+		t.Error("expected error when MinDistinct exceeds the character set size")
+	}
+}
+
+// TestGeneratePassword_BalanceCase checks that uppercase and lowercase counts stay
+// within the tolerance and that infeasible settings are rejected.
+func TestGeneratePassword_BalanceCase(t *testing.T) {
+	for _, tolerance := range []int{0, 2} {
+		opt := PasswordOptions{
+			Length:          20,
+			UseSpecialChars: true,
+			UseNumbers:      true,
+			UseUpper:        true,
+			UseLower:        true,
+			Count:           100,
+			BalanceCase:     true,
+			CaseTolerance:   tolerance,
+		}
+		passwords, err := GeneratePassword(opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		limit := max(tolerance, 1)
+		for _, gp := range passwords {
+			diff := countIn(gp.Value, uppercase) - countIn(gp.Value, lowercase)
+			if diff > limit || -diff > limit {
+				t.Errorf("password %s has case difference %d, tolerance %d", gp.Value, diff, limit)
+			}
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordOptions{Length: 12, UseLower: true, UseNumbers: true, Count: 1, BalanceCase: true}); err == nil {
+		t.Error("expected error when uppercase letters are disabled")
+	}
+	if _, err := GeneratePassword(PasswordOptions{Length: 12, UseUpper: true, UseLower: true, Count: 1, MaxUpper: 3, BalanceCase: true}); err == nil {
+		t.Error("expected error when a letter maximum makes balancing impossible")
+	}
+}
+
+// TestGeneratePassword_ScatterDigits checks that no password ends in a run of digits.
+func TestGeneratePassword_ScatterDigits(t *testing.T) {
+	opt := PasswordOptions{Length: 6, UseNumbers: true, UseLower: true, Count: 300, ScatterDigits: true}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		tail := gp.Value[len(gp.Value)-digitTailLength:]
+		if strings.Trim(tail, numbers) == "" {
+			t.Errorf("password %s ends in digits", gp.Value)
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordOptions{Length: 6, UseNumbers: true, Count: 1, ScatterDigits: true}); err == nil {
+		t.Error("expected error when numbers are the only set")
+	}
+}
+
+// TestGenerateWithResult_Retries checks that retries are counted only when candidates are rejected.
+func TestGenerateWithResult_Retries(t *testing.T) {
+	result, err := GenerateWithResult(PasswordOptions{Length: 12, UseLower: true, UseNumbers: true, Count: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Passwords) != 10 || result.Retries != 0 {
+		t.Errorf("expected 10 passwords and no retries, got %d and %d", len(result.Passwords), result.Retries)
+	}
+
+	result, err = GenerateWithResult(PasswordOptions{Length: 8, UseNumbers: true, Count: 10, MinDistinct: 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Retries == 0 {
+		t.Error("expected retries for tightly constrained options")
+	}
+}
+
+// TestGeneratePassword_FullASCIISymbols checks that symbols outside the curated set
+// can appear and that PasswordEntropy counts them as special.
+func TestGeneratePassword_FullASCIISymbols(t *testing.T) {
+	opt := PasswordOptions{Length: 32, UseSpecialChars: true, Count: 50, FullASCIISymbols: true}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var all strings.Builder
+	for _, gp := range passwords {
+		if strings.Trim(gp.Value, fullASCIISymbols) != "" {
+			t.Errorf("password %s contains characters outside printable ASCII punctuation", gp.Value)
+		}
+		all.WriteString(gp.Value)
+	}
+	for _, r := range "`\"\\'~" {
+		if !strings.ContainsRune(all.String(), r) {
+			t.Errorf("expected %q to appear across 1600 symbols", r)
+		}
+	}
+
+	// 32 symbols: 2 * log2(32) = 10 bits
+	if entropy, _, err := PasswordEntropy("`\""); err != nil || entropy != 10 {
+		t.Errorf("expected 10 bits for two full-range symbols, got %.2f (%v)", entropy, err)
+	}
+	if want := 32 * math.Log2(32); passwords[0].Entropy != want {
+		t.Errorf("expected generated entropy %.2f, got %.2f", want, passwords[0].Entropy)
+	}
+
+	if _, err := GeneratePassword(PasswordOptions{Length: 8, UseLower: true, Count: 1, FullASCIISymbols: true}); err == nil {
+		t.Error("expected error when special characters are disabled")
+	}
+}
+
+// TestGeneratePassword_Reject checks that rejected candidates are regenerated and
+// that Reject sees the complete value.
+func TestGeneratePassword_Reject(t *testing.T) {
+	var calls int
+	opt := PasswordOptions{
+		Length:   4,
+		UseLower: true,
+		Count:    20,
+		Prefix:   "t-",
+		Reject: func(value string) bool {
+			calls++
+			if !strings.HasPrefix(value, "t-") {
+				t.Errorf("expected Reject to see the prefix, got %s", value)
+			}
+			return strings.ContainsRune(value, 'a')
+		},
+	}
+	result, err := GenerateWithResult(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range result.Passwords {
+		if strings.ContainsRune(gp.Value, 'a') {
+			t.Errorf("rejected password %s was returned", gp.Value)
+		}
+	}
+	if calls != len(result.Passwords)+result.Retries {
+		t.Errorf("expected %d Reject calls, got %d", len(result.Passwords)+result.Retries, calls)
+	}
+}
+
+// TestGeneratePassword_OnGenerated checks that the callback sees every password
+// without them being buffered, and that its error aborts generation.
+func TestGeneratePassword_OnGenerated(t *testing.T) {
+	var seen []string
+	opt := PasswordOptions{
+		Length:   10,
+		UseLower: true,
+		Count:    7,
+		OnGenerated: func(gp GeneratedPassword) error {
+			seen = append(seen, gp.Value)
+			return nil
+		},
+	}
+	result, err := GenerateWithResult(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 7 {
+		t.Fatalf("expected 7 callbacks, got %d", len(seen))
+	}
+	for i, value := range seen {
+		if len(value) != 10 {
+			t.Errorf("callback %d saw %q, expected 10 characters", i, value)
+		}
+	}
+	if result.Passwords != nil {
+		t.Errorf("expected no buffered passwords, got %d", len(result.Passwords))
+	}
+
+	errVault := errors.New("vault unavailable")
+	calls := 0
+	opt.OnGenerated = func(GeneratedPassword) error {
+		calls++
+		if calls == 3 {
+			return errVault
+		}
+		return nil
+	}
+	if _, err := GeneratePassword(opt); !errors.Is(err, errVault) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected generation to stop after 3 callbacks, got %d", calls)
+	}
+}
+
+// TestGeneratePassword_CaseInsensitive checks that only one letter case appears,
+// that entropy counts 26 letters, and that enabling both cases is rejected.
+func TestGeneratePassword_CaseInsensitive(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          16,
+		UseUpper:        true,
+		UseNumbers:      true,
+		Count:           50,
+		CaseInsensitive: true,
+		ExtraChars:      "abcé",
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if containsAny(gp.Value, lowercase) {
+			t.Errorf("password %s mixes letter cases", gp.Value)
+		}
+		// 26 letters, 10 digits and "é"; "abc" fold onto uppercase letters.
+		if want := 16 * math.Log2(37); math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected entropy %.2f, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	opt.UseLower = true
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when both letter cases are enabled")
+	}
+}
+
+// TestGeneratePassword_AvoidOldChars checks that none of the old password's
+// characters appear in the new one and that emptying a class is rejected.
+func TestGeneratePassword_AvoidOldChars(t *testing.T) {
+	old := "Tr0ub4dor&3xyz!"
+	opt := PasswordOptions{
+		Length:          20,
+		UseUpper:        true,
+		UseLower:        true,
+		UseNumbers:      true,
+		UseSpecialChars: true,
+		Count:           50,
+		AvoidOldChars:   old,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if containsAny(gp.Value, old) {
+			t.Errorf("password %s contains a character of the old password", gp.Value)
+		}
+	}
+
+	opt.AvoidOldChars = numbers
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when excluded characters empty the digits")
+	}
+}
+
+// TestGeneratePassword_RequireCategory checks that a rune from each required
+// Unicode category appears and that unsatisfiable categories are rejected.
+func TestGeneratePassword_RequireCategory(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          8,
+		UseLower:        true,
+		UseNumbers:      true,
+		Count:           50,
+		ExtraChars:      "🔑🎲ДЖ",
+		RequireCategory: []string{"So", "Lu"},
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if !strings.ContainsAny(gp.Value, "🔑🎲") {
+			t.Errorf("password %s contains no emoji", gp.Value)
+		}
+		if !strings.ContainsAny(gp.Value, "ДЖ") {
+			t.Errorf("password %s contains no uppercase letter", gp.Value)
+		}
+	}
+
+	for _, categories := range [][]string{{"Xx"}, {"Sm"}, {"So", "Lu", "Nd", "Ll", "Lo", "Sc", "Pd"}} {
+		opt.RequireCategory = categories
+		if _, err := GeneratePassword(opt); err == nil {
+			t.Errorf("expected error for categories %v", categories)
+		}
+	}
+}
+
+// TestGeneratePassword_QAMarker checks that the marker appears on top of an
+// otherwise valid password and that non-alphanumeric markers are rejected.
+func TestGeneratePassword_QAMarker(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          12,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           50,
+		QAMarker:        "Qa",
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		i := strings.Index(gp.Value, "Qa")
+		if i < 0 {
+			t.Fatalf("password %s does not contain the marker", gp.Value)
+		}
+		random := gp.Value[:i] + gp.Value[i+2:]
+		if len(random) != 12 {
+			t.Errorf("expected 12 random characters, got %q", random)
+		}
+		for _, set := range []string{specialChars, numbers, uppercase, lowercase} {
+			if !containsAny(random, set) {
+				t.Errorf("password %s has no character from %q", gp.Value, set)
+			}
+		}
+	}
+
+	opt.QAMarker = "Q!"
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for a marker with a special character")
+	}
+
+	// The marker must come from the character set and fit the constraints.
+	invalid := map[string]func(*PasswordOptions){
+		"case-insensitive": func(o *PasswordOptions) { o.UseUpper, o.CaseInsensitive = false, true },
+		"no uppercase":     func(o *PasswordOptions) { o.UseUpper = false; o.QAMarker = "Q9" },
+		"avoided":          func(o *PasswordOptions) { o.AvoidOldChars = "Qa" },
+		"max upper":        func(o *PasswordOptions) { o.MaxUpper = 1; o.QAMarker = "QQQ" },
+	}
+	for name, modify := range invalid {
+		o := opt
+		o.QAMarker = "Qa"
+		modify(&o)
+		if _, err := GeneratePassword(o); err == nil {
+			t.Errorf("%s: expected error for marker %q", name, o.QAMarker)
+		}
+	}
+
+	// Constraints are checked on the marked password.
+	opt.QAMarker = "QQ"
+	opt.MaxUpper = 3
+	passwords, err = GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if n := countIn(gp.Value, uppercase); n > 3 {
+			t.Errorf("password %s has %d uppercase letters including the marker, more than 3", gp.Value, n)
+		}
+	}
+}
+
+// TestGeneratePassword_MinClasses checks that at least MinClasses sets appear,
+// that lengths below the number of selected sets are allowed, and that
+// requiring more classes than selected is rejected.
+func TestGeneratePassword_MinClasses(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          3,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           200,
+		MinClasses:      3,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		classes := 0
+		for _, set := range []string{specialChars, numbers, uppercase, lowercase} {
+			if containsAny(gp.Value, set) {
+				classes++
+			}
+		}
+		if classes < 3 {
+			t.Errorf("password %s has only %d character classes", gp.Value, classes)
+		}
+	}
+
+	opt.UseSpecialChars = false
+	opt.MinClasses = 4
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when more classes are required than selected")
+	}
+}
+
+// TestGeneratePassword_XMLSafe checks that no XML-escaped character appears and
+// that entropy counts the reduced special set.
+func TestGeneratePassword_XMLSafe(t *testing.T) {
+	for _, full := range []bool{false, true} {
+		opt := PasswordOptions{
+			Length:           16,
+			UseSpecialChars:  true,
+			UseNumbers:       true,
+			UseUpper:         true,
+			UseLower:         true,
+			Count:            200,
+			FullASCIISymbols: full,
+			ExtraChars:       "<é",
+			XMLSafe:          true,
+		}
+		passwords, err := GeneratePassword(opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		special := len(specialChars) - 3
+		if full {
+			special = len(fullASCIISymbols) - 5
+		}
+		want := 16 * math.Log2(float64(26+26+10+special+1))
+		for _, gp := range passwords {
+			if strings.ContainsAny(gp.Value, xmlUnsafeChars) {
+				t.Errorf("password %s contains an XML-unsafe character", gp.Value)
+			}
+			if math.Abs(gp.Entropy-want) > 1e-9 {
+				t.Errorf("expected entropy %.2f, got %.2f", want, gp.Entropy)
+			}
+		}
+	}
+}
+
+// TestGeneratePassword_WhitespaceEdges checks that passwords never start or end
+// with whitespace, even when a custom special set or the extra characters
+// contain spaces, and that a whitespace-only set still appears in the interior.
+func TestGeneratePassword_WhitespaceEdges(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          3,
+		UseSpecialChars: true,
+		UseLower:        true,
+		SpecialChars:    " ",
+		Count:           200,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		runes := []rune(gp.Value)
+		if unicode.IsSpace(runes[0]) || unicode.IsSpace(runes[len(runes)-1]) {
+			t.Errorf("password has whitespace at an edge: %q", gp.Value)
+		}
+		if !strings.Contains(gp.Value, " ") {
+			t.Errorf("password lost the required space: %q", gp.Value)
+		}
+	}
+
+	opt.Length, opt.ExtraChars = 8, "\u00a0\u3000"
+	passwords, err = GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		runes := []rune(gp.Value)
+		if unicode.IsSpace(runes[0]) || unicode.IsSpace(runes[len(runes)-1]) {
+			t.Errorf("password has whitespace at an edge: %q", gp.Value)
+		}
+	}
+
+	opt.Length = 2
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when a whitespace-only set cannot fit between the edges")
+	}
+	opt = PasswordOptions{Length: 8, UseSpecialChars: true, SpecialChars: "!a", Count: 1}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for a letter in the custom special characters")
+	}
+}
+
+// TestGeneratePassword_RealSeparator checks that the separator is a real part of
+// the value at every interval, and that it adds no entropy.
+func TestGeneratePassword_RealSeparator(t *testing.T) {
+	opt := PasswordOptions{
+		Length:         12,
+		UseUpper:       true,
+		UseNumbers:     true,
+		RealSeparator:  '-',
+		SeparatorEvery: 4,
+		Prefix:         "wifi_",
+		Count:          20,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		body, ok := strings.CutPrefix(gp.Value, "wifi_")
+		if !ok || len(body) != 14 {
+			t.Fatalf("expected the prefix and 14 characters, got %q", gp.Value)
+		}
+		for i, r := range body {
+			if (i == 4 || i == 9) != (r == '-') {
+				t.Errorf("unexpected character %q at position %d of %q", r, i, body)
+			}
+		}
+		if want := 12 * math.Log2(36); math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected %.2f bits, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	for _, bad := range []PasswordOptions{
+		{Length: 12, UseUpper: true, Count: 1, RealSeparator: '-'},
+		{Length: 12, UseUpper: true, Count: 1, SeparatorEvery: 4},
+		{Length: 12, UseUpper: true, Count: 1, RealSeparator: ' ', SeparatorEvery: 4},
+		{Length: 12, UseSpecialChars: true, Count: 1, RealSeparator: '-', SeparatorEvery: 4},
+		{Length: 12, UseLower: true, Count: 1, RealSeparator: '<', SeparatorEvery: 4, XMLSafe: true},
+		{Length: 12, UseLower: true, Count: 1, RealSeparator: '-', SeparatorEvery: 4, AvoidOldChars: "-"},
+		{Length: 12, UseLower: true, Count: 1, RealSeparator: 'O', SeparatorEvery: 4, ExcludeAmbiguous: true},
+		{Length: 12, UseLower: true, Count: 1, RealSeparator: '!', SeparatorEvery: 4, NoShift: true},
+	} {
+		if _, err := GeneratePassword(bad); err == nil {
+			t.Errorf("expected error for separator %q every %d", bad.RealSeparator, bad.SeparatorEvery)
+		}
+	}
+
+	// Without Shift, an unshifted separator is still allowed.
+	if _, err := GeneratePassword(PasswordOptions{Length: 12, UseLower: true, Count: 1, RealSeparator: '-', SeparatorEvery: 4, NoShift: true}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestGeneratePassword_IdentifierSafe checks that identifier-safe passwords are
+// valid identifiers and that entropy counts the reduced set.
+func TestGeneratePassword_IdentifierSafe(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          12,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		IdentifierSafe:  true,
+		ExtraChars:      "é-",
+		Count:           200,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	identifier := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	for _, gp := range passwords {
+		if !identifier.MatchString(gp.Value) {
+			t.Errorf("password %q is not a valid identifier", gp.Value)
+		}
+		if want := 12 * math.Log2(63); math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected %.2f bits, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	opt = PasswordOptions{Length: 8, UseNumbers: true, IdentifierSafe: true, Count: 1}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when only digits are selected")
+	}
+
+	opt = PasswordOptions{Length: 12, UseLower: true, UseNumbers: true, IdentifierSafe: true, Count: 20}
+	for prefix, valid := range map[string]bool{"9": false, "9ab": false, "_9": true, "a9": true} {
+		opt.Prefix = prefix
+		passwords, err := GeneratePassword(opt)
+		if !valid {
+			if err == nil {
+				t.Errorf("expected error for prefix %q", prefix)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for prefix %q: %v", prefix, err)
+		}
+		for _, gp := range passwords {
+			if !identifier.MatchString(gp.Value) {
+				t.Errorf("password %q is not a valid identifier", gp.Value)
+			}
+		}
+	}
+}
+
+// TestValidateOptions checks that options are validated after policies widen
+// them, without generating a password.
+func TestValidateOptions(t *testing.T) {
+	opt := PasswordOptions{Length: 4, UseLower: true, Count: 1, Policies: []Policy{PCIDSS()}}
+	if err := ValidateOptions(opt); err != nil {
+		t.Errorf("expected the policy to widen the options, got %v", err)
+	}
+	opt.OnGenerated = func(GeneratedPassword) error { return errors.New("generated") }
+	if err := ValidateOptions(opt); err != nil {
+		t.Errorf("expected no password to be generated, got %v", err)
+	}
+	if err := ValidateOptions(PasswordOptions{Length: 12, Count: 1}); err == nil {
+		t.Error("expected error when no character set is selected")
+	}
+}
+
+// TestGeneratePassword_MaxLength checks that absurd lengths are rejected by
+// default and accepted once MaxLength is raised.
+func TestGeneratePassword_MaxLength(t *testing.T) {
+	opt := PasswordOptions{Length: 1_000_000_000, UseLower: true, Count: 1}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for a length of a billion")
+	}
+
+	opt.Length = DefaultMaxLength + 1
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Errorf("expected error for a length of %d", opt.Length)
+	}
+	opt.MaxLength = 2 * DefaultMaxLength
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(passwords[0].Value); got != DefaultMaxLength+1 {
+		t.Errorf("expected %d characters, got %d", DefaultMaxLength+1, got)
+	}
+
+	opt.MaxLength = -1
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for a negative maximum length")
+	}
+}
+
+// TestGeneratePassword_ExcludeAmbiguous checks that the default ambiguous
+// characters are excluded, and that a custom set excludes exactly its
+// characters instead.
+func TestGeneratePassword_ExcludeAmbiguous(t *testing.T) {
+	opt := PasswordOptions{
+		Length:           32,
+		UseSpecialChars:  true,
+		UseNumbers:       true,
+		UseUpper:         true,
+		UseLower:         true,
+		ExcludeAmbiguous: true,
+		Count:            50,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if strings.ContainsAny(gp.Value, defaultAmbiguousChars) {
+			t.Errorf("password %s contains an ambiguous character", gp.Value)
+		}
+	}
+	if want := 32 * math.Log2(89-6); math.Abs(passwords[0].Entropy-want) > 1e-9 {
+		t.Errorf("expected %.2f bits without 6 characters, got %.2f", want, passwords[0].Entropy)
+	}
+
+	// A font where only rn/m and vv/w look alike.
+	opt.AmbiguousChars = "rnmvw"
+	pool := newPools(opt).charset
+	for _, r := range buildCharset(PasswordOptions{UseSpecialChars: true, UseNumbers: true, UseUpper: true, UseLower: true}) {
+		if excluded := strings.ContainsRune(opt.AmbiguousChars, r); excluded == slices.Contains(pool, r) {
+			t.Errorf("expected %q to be excluded: %v", r, excluded)
+		}
+	}
+	custom, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 32 * math.Log2(89-5); math.Abs(custom[0].Entropy-want) > 1e-9 {
+		t.Errorf("expected %.2f bits without 5 characters, got %.2f", want, custom[0].Entropy)
+	}
+
+	opt.ExcludeAmbiguous = false
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for ambiguous characters without excluding them")
+	}
+}
+
+// TestGeneratePassword_RequireFromGroups checks that every password contains a
+// character from each required group, and that impossible groups are rejected.
+func TestGeneratePassword_RequireFromGroups(t *testing.T) {
+	opt := PasswordOptions{
+		Length:            6,
+		UseSpecialChars:   true,
+		UseLower:          true,
+		RequireFromGroups: [][]rune{[]rune("!@#"), []rune("$%^"), []rune("xyz")},
+		Count:             200,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		for _, group := range opt.RequireFromGroups {
+			if !strings.ContainsAny(gp.Value, string(group)) {
+				t.Errorf("password %s has no character from %q", gp.Value, string(group))
+			}
+		}
+	}
+
+	opt.Length = 4
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error when the groups do not fit the length")
+	}
+	opt.Length, opt.RequireFromGroups = 8, [][]rune{[]rune("ABC")}
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for a group outside the pool")
+	}
+}
+
+// TestGeneratePassword_UpperRatio checks that the share of uppercase letters
+// over a large sample approximates UpperRatio while every password keeps a
+// letter of each case.
+func TestGeneratePassword_UpperRatio(t *testing.T) {
+	for _, ratio := range []float64{0.2, 0.8} {
+		passwords, err := GeneratePassword(PasswordOptions{
+			Length:     40,
+			UseUpper:   true,
+			UseLower:   true,
+			UseNumbers: true,
+			Count:      500,
+			UpperRatio: ratio,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var upper, letters int
+		for _, p := range passwords {
+			u, l := countIn(p.Value, uppercase), countIn(p.Value, lowercase)
+			if u == 0 || l == 0 {
+				t.Errorf("password %s lacks a letter case", p.Value)
+			}
+			upper += u
+			letters += u + l
+		}
+		if got := float64(upper) / float64(letters); math.Abs(got-ratio) > 0.03 {
+			t.Errorf("expected an uppercase share near %.2f, got %.3f", ratio, got)
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordOptions{Length: 12, UseLower: true, Count: 1, UpperRatio: 0.5}); err == nil {
+		t.Error("expected an error for an upper ratio without uppercase letters")
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// natoAlphabet holds the NATO phonetic words for the letters a to z.
+var natoAlphabet = [26]string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+	"quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey",
+	"x-ray", "yankee", "zulu",
+}
+
+// digitNames holds the spoken names of the digits 0 to 9.
+var digitNames = [10]string{"Zero", "One", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine"}
+
+// symbolNames holds the spoken names of the printable ASCII symbols and space.
+var symbolNames = map[rune]string{
+	' ': "Space", '!': "Exclamation", '"': "Double Quote", '#': "Hash", '$': "Dollar",
+	'%': "Percent", '&': "Ampersand", '\'': "Apostrophe", '(': "Open Parenthesis",
+	')': "Close Parenthesis", '*': "Asterisk", '+': "Plus", ',': "Comma", '-': "Hyphen",
+	'.': "Period", '/': "Slash", ':': "Colon", ';': "Semicolon", '<': "Less Than",
+	'=': "Equals", '>': "Greater Than", '?': "Question Mark", '@': "At Sign",
+	'[': "Open Bracket", '\\': "Backslash", ']': "Close Bracket", '^': "Caret",
+	'_': "Underscore", '`': "Backtick", '{': "Open Brace", '|': "Pipe",
+	'}': "Close Brace", '~': "Tilde",
+}
+
+// Phonetic spells s for reading aloud, one comma-separated item per rune:
+// lowercase letters use the NATO alphabet ("alpha"), uppercase letters are
+// prefixed with "Capital" ("Capital Alpha"), and digits and ASCII symbols are
+// named ("Three", "Exclamation"). Any other rune is quoted as is.
+func Phonetic(s string) string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z':
+			parts = append(parts, natoAlphabet[r-'a'])
+		case 'A' <= r && r <= 'Z':
+			word := natoAlphabet[r-'A']
+			parts = append(parts, "Capital "+string(unicode.ToUpper(rune(word[0])))+word[1:])
+		case '0' <= r && r <= '9':
+			parts = append(parts, digitNames[r-'0'])
+		default:
+			if name, ok := symbolNames[r]; ok {
+				parts = append(parts, name)
+			} else {
+				parts = append(parts, fmt.Sprintf("%q", r))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,24 @@
+package generator
+
+import "testing"
+
+// TestPhonetic checks the spelling of letters in both cases, digits, symbols and other runes.
+func TestPhonetic(t *testing.T) {
+	tests := map[string]string{
+		"aB3!":  "alpha, Capital Bravo, Three, Exclamation",
+		"Xx0":   "Capital X-ray, x-ray, Zero",
+		"`\\ ~": "Backtick, Backslash, Space, Tilde",
+		"é":     "'é'",
+		"":      "",
+	}
+	for in, want := range tests {
+		if got := Phonetic(in); got != want {
+			t.Errorf("Phonetic(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for _, r := range fullASCIISymbols {
+		if _, ok := symbolNames[r]; !ok {
+			t.Errorf("no name for symbol %q", r)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy describes a set of password requirements, such as those published by
+// an auditor or a website. Zero values mean "no requirement".
+type Policy struct {
+	Name           string  `json:"name"`            // Name used in error messages
+	MinLength      int     `json:"min_length"`      // Minimum number of characters
+	MaxLength      int     `json:"max_length"`      // Maximum number of characters
+	RequireUpper   bool    `json:"require_upper"`   // Require at least one uppercase letter
+	RequireLower   bool    `json:"require_lower"`   // Require at least one lowercase letter
+	RequireNumbers bool    `json:"require_numbers"` // Require at least one digit
+	RequireSpecial bool    `json:"require_special"` // Require at least one special character
+	MinEntropy     float64 `json:"min_entropy"`     // Minimum entropy in bits, as computed by PasswordEntropy
+}
+
+// PCIDSS returns the password requirements of PCI DSS 4.0 (requirement 8.3.6):
+// at least 12 characters with uppercase letters, lowercase letters and digits.
+func PCIDSS() Policy {
+	return Policy{
+		Name:           "PCI DSS 4.0",
+		MinLength:      12,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireNumbers: true,
+	}
+}
+
+// PolicyError reports a policy that a password does not or cannot satisfy.
+type PolicyError struct {
+	Policy     string   // Name of the failing policy
+	Violations []string // Human-readable description of each violation
+}
+
+// Error implements the error interface.
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("policy %q not satisfied: %s", e.Policy, strings.Join(e.Violations, "; "))
+}
+
+// Check validates password against the policy and returns a description of
+// every violation, or nil if the password satisfies it. RequireSpecial is met
+// by any printable ASCII punctuation; use CheckWithSpecials when a custom
+// special set, such as one containing a space, must count as well.
+func (p Policy) Check(password string) []string {
+	return p.CheckWithSpecials(password, "")
+}
+
+// CheckWithSpecials is like Check, but RequireSpecial is also met by any
+// character of specials, such as the set returned by SpecialSet for the
+// options the password was generated with.
+func (p Policy) CheckWithSpecials(password, specials string) []string {
+	var violations []string
+	length := len([]rune(password))
+	if p.MinLength > 0 && length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters long", p.MaxLength))
+	}
+	if p.RequireUpper && !strings.ContainsAny(password, uppercase) {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !strings.ContainsAny(password, lowercase) {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireNumbers && !strings.ContainsAny(password, numbers) {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSpecial && !strings.ContainsAny(password, fullASCIISymbols+specials) {
+		violations = append(violations, "must contain a special character")
+	}
+	if p.MinEntropy > 0 {
+		entropy, _, err := PasswordEntropy(password)
+		if err != nil || entropy < p.MinEntropy {
+			violations = append(violations, fmt.Sprintf("must have at least %.2f bits of entropy", p.MinEntropy))
+		}
+	}
+	return violations
+}
+
+// applyPolicies widens opt to meet the union of the requirements of
+// opt.Policies: the largest minimum length and every required character set.
+// It returns a *PolicyError if a policy's maximum length is below that union.
+func applyPolicies(opt PasswordOptions) (PasswordOptions, error) {
+	for _, p := range opt.Policies {
+		opt.Length = max(opt.Length, p.MinLength)
+		opt.UseUpper = opt.UseUpper || p.RequireUpper
+		opt.UseLower = opt.UseLower || p.RequireLower
+		opt.UseNumbers = opt.UseNumbers || p.RequireNumbers
+		opt.UseSpecialChars = opt.UseSpecialChars || p.RequireSpecial
+	}
+	for _, p := range opt.Policies {
+		if p.MaxLength > 0 && opt.Length > p.MaxLength {
+			return opt, &PolicyError{
+				Policy: p.Name,
+				Violations: []string{fmt.Sprintf("allows at most %d characters but the combined requirements need %d",
+					p.MaxLength, opt.Length)},
+			}
+		}
+	}
+	return opt, nil
+}
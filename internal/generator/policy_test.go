@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestGeneratePassword_Policies checks that passwords generated for two compatible
+// policies satisfy both of them.
+func TestGeneratePassword_Policies(t *testing.T) {
+	symbols := Policy{Name: "symbols", MinLength: 14, RequireSpecial: true}
+	mixed := Policy{Name: "mixed", MinLength: 10, RequireUpper: true, RequireNumbers: true, MinEntropy: 60}
+	opt := PasswordOptions{
+		Length:   8,
+		UseLower: true,
+		Count:    10,
+		Policies: []Policy{symbols, mixed},
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if len([]rune(gp.Value)) != 14 {
+			t.Errorf("expected length 14 from the largest minimum, got %d", len([]rune(gp.Value)))
+		}
+		for _, p := range opt.Policies {
+			if violations := p.Check(gp.Value); len(violations) > 0 {
+				t.Errorf("password %s violates policy %s: %v", gp.Value, p.Name, violations)
+			}
+		}
+	}
+}
+
+// TestGeneratePassword_ContradictoryPolicies checks that contradictory policies
+// return a PolicyError naming the policy that cannot be met.
+func TestGeneratePassword_ContradictoryPolicies(t *testing.T) {
+	opt := PasswordOptions{
+		Length:   8,
+		UseLower: true,
+		Count:    1,
+		Policies: []Policy{
+			{Name: "long", MinLength: 16},
+			{Name: "short", MaxLength: 12},
+		},
+	}
+	_, err := GeneratePassword(opt)
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected PolicyError, got %v", err)
+	}
+	if policyErr.Policy != "short" {
+		t.Errorf("expected failing policy %q, got %q", "short", policyErr.Policy)
+	}
+}
+
+// TestPolicy_Check checks that each violated requirement is reported.
+func TestPolicy_Check(t *testing.T) {
+	p := Policy{Name: "strict", MinLength: 12, RequireUpper: true, RequireNumbers: true, RequireSpecial: true}
+	if violations := p.Check("abc"); len(violations) != 4 {
+		t.Errorf("expected 4 violations, got %d: %v", len(violations), violations)
+	}
+	if violations := p.Check("Abcdefgh1!xy"); violations != nil {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+// TestGeneratePassword_PolicyCustomSpecials checks that a policy requiring a
+// special character accepts the configured special set, even a space outside
+// the ASCII punctuation.
+func TestGeneratePassword_PolicyCustomSpecials(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          12,
+		UseSpecialChars: true,
+		UseLower:        true,
+		SpecialChars:    " ",
+		Count:           20,
+		Policies:        []Policy{{Name: "special", RequireSpecial: true}},
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if violations := opt.Policies[0].CheckWithSpecials(gp.Value, SpecialSet(opt)); violations != nil {
+			t.Errorf("password %q violates the policy: %v", gp.Value, violations)
+		}
+	}
+	if violations := opt.Policies[0].Check("abc def"); violations == nil {
+		t.Error("expected Check to ignore a space without the custom set")
+	}
+}
+
+// TestPCIDSS checks that passwords generated under the PCI DSS policy satisfy it,
+// even when the options ask for less.
+func TestPCIDSS(t *testing.T) {
+	opt := PasswordOptions{Length: 8, UseLower: true, Count: 50, Policies: []Policy{PCIDSS()}}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if violations := PCIDSS().Check(gp.Value); violations != nil {
+			t.Errorf("password %s violates PCI DSS: %v", gp.Value, violations)
+		}
+	}
+	if violations := PCIDSS().Check("password1234"); len(violations) != 1 {
+		t.Errorf("expected a single violation for a password without uppercase, got %v", violations)
+	}
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// phonotactics describes the syllables of a language as an onset (one or more
+// consonants), a vowel nucleus and an optional coda of consonants.
+type phonotactics struct {
+	onsets, nuclei, codas []string
+}
+
+// languageRules holds the bundled phonotactic rules, keyed by language code.
+// Onsets and codas hold only consonants and nuclei only the vowels a, e, i, o
+// and u, so a cluster never runs into a vowel group. Every language has a
+// single-letter onset, nucleus and coda, so any length from 2 can be spelled
+// exactly.
+var languageRules = map[string]phonotactics{
+	"en": {
+		onsets: strings.Fields("b c d f g h j k l m n p r s t v w y z bl br ch cl cr dr fl fr gl gr pl pr sc sh sk sl sm sn sp st sw th tr tw wh"),
+		nuclei: strings.Fields("a e i o u ai ea ee oa oo ou"),
+		codas:  strings.Fields("b ck d f g k l m n nd ng nk nt p r rd rk rm rn s sh sk st t th x"),
+	},
+	"es": {
+		onsets: strings.Fields("b c ch d f g j l ll m n p r s t v z bl br cl cr dr fl fr gl gr pl pr tr"),
+		nuclei: strings.Fields("a e i o u ia ie io ua ue"),
+		codas:  strings.Fields("d l n r s z"),
+	},
+	"it": {
+		onsets: strings.Fields("b c ch d f g gh gl gn l m n p r s t v z br cr dr fr gr pr tr sc sp st"),
+		nuclei: strings.Fields("a e i o u ia io uo"),
+		codas:  strings.Fields("l n r s"),
+	},
+}
+
+// PronounceableLanguages returns the codes of the bundled languages, sorted.
+func PronounceableLanguages() []string {
+	return slices.Sorted(maps.Keys(languageRules))
+}
+
+// PronounceableOptions defines the options for pronounceable password generation.
+type PronounceableOptions struct {
+	Length   int    `json:"length"`   // Number of letters in each password
+	Count    int    `json:"count"`    // Number of passwords to generate
+	Language string `json:"language"` // Language whose syllable rules are followed ("" means "en")
+}
+
+// rules returns the phonotactic rules of opt.Language.
+func (opt PronounceableOptions) rules() (phonotactics, error) {
+	lang := cmp.Or(opt.Language, "en")
+	rules, ok := languageRules[lang]
+	if !ok {
+		return phonotactics{}, fmt.Errorf("unknown language %q (expected %s)", lang, strings.Join(PronounceableLanguages(), ", "))
+	}
+	return rules, nil
+}
+
+// fitting returns the units that leave a remainder accepted by ok when taken
+// from remaining letters.
+func fitting(units []string, remaining int, ok func(rest int) bool) []string {
+	var fit []string
+	for _, u := range units {
+		if rest := remaining - len(u); rest >= 0 && ok(rest) {
+			fit = append(fit, u)
+		}
+	}
+	return fit
+}
+
+// onsetsFor returns the onsets that leave room for a nucleus. Together with
+// nucleiFor and codasFor it constrains each choice so the word can still be
+// completed at exactly the requested length.
+func (p phonotactics) onsetsFor(remaining int) []string {
+	return fitting(p.onsets, remaining, func(rest int) bool { return rest >= 1 })
+}
+
+// nucleiFor returns the nuclei that fit in the remaining letters.
+func (p phonotactics) nucleiFor(remaining int) []string {
+	return fitting(p.nuclei, remaining, func(rest int) bool { return true })
+}
+
+// codasFor returns the codas, including the empty one, after which the word
+// either ends or has room for another syllable of at least two letters.
+func (p phonotactics) codasFor(remaining int) []string {
+	return fitting(append([]string{""}, p.codas...), remaining, func(rest int) bool { return rest == 0 || rest >= 2 })
+}
+
+// minEntropy returns the fewest bits of choice any word of length letters can
+// be generated with, the guaranteed entropy of GeneratePronounceable.
+func (p phonotactics) minEntropy(length int) float64 {
+	syllable := make([]float64, length+1) // Bits from the start of a syllable with i letters left
+	for r := 1; r <= length; r++ {
+		syllable[r] = math.Inf(1)
+	}
+	// Each stage only moves to smaller remainders, so fill them in increasing order.
+	stage := func(units []string, next func(int) float64, remaining int) float64 {
+		best := math.Inf(1)
+		for _, u := range units {
+			best = min(best, next(remaining-len(u)))
+		}
+		return math.Log2(float64(len(units))) + best
+	}
+	for r := 2; r <= length; r++ {
+		syllable[r] = stage(p.onsetsFor(r), func(r int) float64 {
+			return stage(p.nucleiFor(r), func(r int) float64 {
+				return stage(p.codasFor(r), func(r int) float64 { return syllable[r] }, r)
+			}, r)
+		}, r)
+	}
+	return syllable[length]
+}
+
+// PronounceableEntropy returns the guaranteed entropy in bits of a password
+// generated with opt: the fewest bits of random choice among the syllable
+// parts that can spell a word of opt.Length letters. Different choices can
+// occasionally spell the same word, such as "st" as one onset or as a coda
+// and an onset, so the figure slightly overstates the real strength.
+func PronounceableEntropy(opt PronounceableOptions) (float64, error) {
+	if opt.Count < 1 {
+		return 0, errors.New("count must be greater than 0")
+	}
+	if opt.Length < 2 {
+		return 0, errors.New("length must be at least 2")
+	}
+	if opt.Length > DefaultMaxLength {
+		return 0, fmt.Errorf("length cannot exceed %d", DefaultMaxLength)
+	}
+	rules, err := opt.rules()
+	if err != nil {
+		return 0, err
+	}
+	return rules.minEntropy(opt.Length), nil
+}
+
+// GeneratePronounceable generates opt.Count lowercase pseudo-words of
+// opt.Length letters that follow the syllable rules of opt.Language: each
+// syllable is a consonant onset, a vowel nucleus and an optional consonant
+// coda, drawn uniformly from the language's lists, so only clusters that look
+// natural in that language appear. Such words are easier to remember but
+// carry far less entropy per letter than random characters.
+func GeneratePronounceable(opt PronounceableOptions) ([]GeneratedPassword, error) {
+	entropy, err := PronounceableEntropy(opt)
+	if err != nil {
+		return nil, err
+	}
+	rules, _ := opt.rules()
+
+	passwords := make([]GeneratedPassword, opt.Count)
+	for i := range passwords {
+		var word strings.Builder
+		for remaining := opt.Length; remaining > 0; {
+			for _, stage := range []func(int) []string{rules.onsetsFor, rules.nucleiFor, rules.codasFor} {
+				units := stage(remaining)
+				n, err := secureRandomInt(len(units))
+				if err != nil {
+					return nil, err
+				}
+				word.WriteString(units[n])
+				remaining -= len(units[n])
+			}
+		}
+		passwords[i] = GeneratedPassword{
+			Value:    word.String(),
+			Strength: classifyEntropy(entropy),
+			Entropy:  entropy,
+		}
+	}
+	return passwords, nil
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestGeneratePronounceable checks that every word has the requested length
+// and uses only the vowel nuclei and consonant clusters of its language: a run
+// of consonants must be an onset at the start, a coda at the end, and a coda
+// followed by an onset in between.
+func TestGeneratePronounceable(t *testing.T) {
+	for _, lang := range PronounceableLanguages() {
+		rules := languageRules[lang]
+		codas := append([]string{""}, rules.codas...)
+		validCluster := func(cluster string, first, last bool) bool {
+			switch {
+			case first:
+				return slices.Contains(rules.onsets, cluster)
+			case last:
+				return slices.Contains(codas, cluster)
+			}
+			for i := range len(cluster) {
+				if slices.Contains(codas, cluster[:i]) && slices.Contains(rules.onsets, cluster[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		passwords, err := GeneratePronounceable(PronounceableOptions{Length: 11, Count: 200, Language: lang})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", lang, err)
+		}
+		for _, p := range passwords {
+			if len(p.Value) != 11 {
+				t.Errorf("%s: expected 11 letters, got %q", lang, p.Value)
+			}
+			runs := splitRuns(p.Value)
+			for i, run := range runs {
+				if strings.ContainsAny(run[:1], "aeiou") {
+					if !slices.Contains(rules.nuclei, run) {
+						t.Errorf("%s: %q has the vowel group %q", lang, p.Value, run)
+					}
+				} else if !validCluster(run, i == 0, i == len(runs)-1) {
+					t.Errorf("%s: %q has the consonant cluster %q", lang, p.Value, run)
+				}
+			}
+		}
+	}
+
+	if _, err := GeneratePronounceable(PronounceableOptions{Length: 10, Count: 1, Language: "xx"}); err == nil {
+		t.Error("expected an error for an unknown language")
+	}
+	if _, err := GeneratePronounceable(PronounceableOptions{Length: 1, Count: 1}); err == nil {
+		t.Error("expected an error for a length below 2")
+	}
+}
+
+// splitRuns splits s into maximal runs of vowels and of consonants.
+func splitRuns(s string) []string {
+	var runs []string
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i == len(s) || strings.ContainsRune("aeiou", rune(s[i])) != strings.ContainsRune("aeiou", rune(s[i-1])) {
+			runs = append(runs, s[start:i])
+			start = i
+		}
+	}
+	return runs
+}
+
+// TestPronounceableEntropy checks that the guaranteed entropy grows with the
+// length and is below that of random lowercase letters.
+func TestPronounceableEntropy(t *testing.T) {
+	short, err := PronounceableEntropy(PronounceableOptions{Length: 8, Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	long, err := PronounceableEntropy(PronounceableOptions{Length: 16, Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if short <= 0 || long <= short {
+		t.Errorf("expected entropy to grow with length, got %.2f and %.2f", short, long)
+	}
+	if random := 16 * 4.7; long >= random {
+		t.Errorf("expected less than %.2f bits for 16 letters, got %.2f", random, long)
+	}
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+	"testing"
+)
+
+// benchmarkSizes are the charset sizes the index benchmarks draw from: digits,
+// lowercase, alphanumerics, the full default pool, and a word list.
+var benchmarkSizes = []struct {
+	name string
+	n    int
+}{
+	{"10", 10},
+	{"26", 26},
+	{"62", 62},
+	{"89", 89},
+	{"2048", 2048},
+}
+
+// bigIntIndex is the rand.Int sampling randomInt uses for deterministic
+// sources, the baseline secureIndex is measured and checked against.
+func bigIntIndex(src io.Reader, n int) (int, error) {
+	v, err := rand.Int(src, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// BenchmarkSecureRandomInt measures the big.Int approach across charset sizes.
+func BenchmarkSecureRandomInt(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := bigIntIndex(rand.Reader, size.n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSecureIndex measures rejection sampling across charset sizes.
+func BenchmarkSecureIndex(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := secureIndex(rand.Reader, size.n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGeneratePassword measures a full default-length password. Compare
+// runs before and after a change with benchstat.
+func BenchmarkGeneratePassword(b *testing.B) {
+	opt := PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           1,
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := GeneratePassword(opt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestSecureIndex_Uniform checks that both index implementations spread draws
+// evenly, using a chi-squared test over a deterministic stream.
+func TestSecureIndex_Uniform(t *testing.T) {
+	const (
+		n       = 7
+		samples = 70000
+		// critical is the chi-squared value for 6 degrees of freedom at p = 0.001.
+		critical = 22.458
+	)
+	impls := map[string]func(io.Reader, int) (int, error){
+		"big.Int":   bigIntIndex,
+		"rejection": secureIndex,
+	}
+	for name, draw := range impls {
+		src := newDeterministicReader([]byte("uniformity " + name))
+		var counts [n]int
+		for range samples {
+			v, err := draw(src, n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v < 0 || v >= n {
+				t.Fatalf("%s: index %d out of range", name, v)
+			}
+			counts[v]++
+		}
+		expected := float64(samples) / n
+		var chi float64
+		for _, c := range counts {
+			d := float64(c) - expected
+			chi += d * d / expected
+		}
+		if chi > critical {
+			t.Errorf("%s: chi-squared %.2f exceeds %.2f, counts %v", name, chi, critical, counts)
+		}
+	}
+}
+
+// TestSecureIndex_InvalidRange checks that a non-positive range is rejected.
+func TestSecureIndex_InvalidRange(t *testing.T) {
+	if _, err := secureIndex(rand.Reader, 0); err == nil {
+		t.Error("expected an error for an empty range")
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// defaultMaxSharedFraction is used by GenerateRotation when
+	// PasswordOptions.MaxSharedFraction is zero.
+	defaultMaxSharedFraction = 0.25
+	// maxRotationAttempts bounds how many candidates GenerateRotation tries.
+	maxRotationAttempts = 100
+)
+
+// sharedFraction returns the fraction of positions at which a and b hold the
+// same rune (a Hamming-style similarity). Positions beyond the shorter string
+// count as different.
+func sharedFraction(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := max(len(ar), len(br))
+	if longest == 0 {
+		return 0
+	}
+	shared := 0
+	for i := 0; i < min(len(ar), len(br)); i++ {
+		if ar[i] == br[i] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(longest)
+}
+
+// GenerateRotation generates a replacement for the old password that shares
+// less than opt.MaxSharedFraction of its positions with it. Count is ignored
+// and a single password is returned. The fraction is measured after removing
+// opt.Prefix from both passwords, since a prefix both share would otherwise
+// count toward the shared positions.
+// Candidates are regenerated up to a bounded number of attempts; an error is
+// returned if no candidate differs sufficiently.
+func GenerateRotation(old string, opt PasswordOptions) (GeneratedPassword, error) {
+	if old == "" {
+		return GeneratedPassword{}, errors.New("old password is empty")
+	}
+	threshold := opt.MaxSharedFraction
+	if threshold == 0 {
+		threshold = defaultMaxSharedFraction
+	}
+	if threshold < 0 || threshold > 1 {
+		return GeneratedPassword{}, errors.New("max shared fraction must be between 0 and 1")
+	}
+
+	opt.Count = 1
+	for range maxRotationAttempts {
+		passwords, err := GeneratePassword(opt)
+		if err != nil {
+			return GeneratedPassword{}, err
+		}
+		if sharedFraction(strings.TrimPrefix(old, opt.Prefix), strings.TrimPrefix(passwords[0].Value, opt.Prefix)) < threshold {
+			return passwords[0], nil
+		}
+	}
+	return GeneratedPassword{}, fmt.Errorf("could not generate a password sharing less than %.2f of positions with the old one after %d attempts",
+		threshold, maxRotationAttempts)
+}
@@ -0,0 +1,76 @@
+package generator
+
+import "testing"
+
+// TestGenerateRotation_Threshold checks that the rotated password shares less than
+// the configured fraction of positions with the old password.
+func TestGenerateRotation_Threshold(t *testing.T) {
+	old := "0123456789"
+	opt := PasswordOptions{
+		Length:            10,
+		UseNumbers:        true,
+		MaxSharedFraction: 0.2,
+	}
+	for range 20 {
+		gp, err := GenerateRotation(old, opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f := sharedFraction(old, gp.Value); f >= 0.2 {
+			t.Errorf("rotated password %s shares %.2f of positions with %s", gp.Value, f, old)
+		}
+	}
+}
+
+// TestGenerateRotation_Prefix checks that a Prefix shared by both passwords is
+// not counted toward the shared positions: with it counted, a 4-character
+// prefix on an 8-character body would always share a third of the positions.
+func TestGenerateRotation_Prefix(t *testing.T) {
+	old := "aws-01234567"
+	opt := PasswordOptions{
+		Length:            8,
+		UseNumbers:        true,
+		Prefix:            "aws-",
+		MaxSharedFraction: 0.3,
+	}
+	for range 20 {
+		gp, err := GenerateRotation(old, opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f := sharedFraction(old[4:], gp.Value[4:]); f >= 0.3 {
+			t.Errorf("rotated password %s shares %.2f of body positions with %s", gp.Value, f, old)
+		}
+	}
+}
+
+// TestGenerateRotation_Invalid checks that an empty old password or an out-of-range
+// threshold returns an error.
+func TestGenerateRotation_Invalid(t *testing.T) {
+	opt := PasswordOptions{Length: 8, UseLower: true}
+	if _, err := GenerateRotation("", opt); err == nil {
+		t.Error("expected error for empty old password")
+	}
+	opt.MaxSharedFraction = 1.5
+	if _, err := GenerateRotation("abcdefgh", opt); err == nil {
+		t.Error("expected error for threshold above 1")
+	}
+}
+
+// TestSharedFraction checks the Hamming-style similarity calculation.
+func TestSharedFraction(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"abcd", "abcd", 1},
+		{"abcd", "wxyz", 0},
+		{"abcd", "abzz", 0.5},
+		{"abcd", "ab", 0.5},
+	}
+	for _, tt := range tests {
+		if got := sharedFraction(tt.a, tt.b); got != tt.want {
+			t.Errorf("sharedFraction(%q, %q) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// seedInfo is the HKDF context string binding derived keys to this generator.
+const seedInfo = "go-passwordgen deterministic generator v1"
+
+// randomSource returns the entropy source for opt: crypto/rand by default, or a
+// deterministic stream derived from opt.Seed when it is set.
+func randomSource(opt PasswordOptions) io.Reader {
+	if len(opt.Seed) == 0 {
+		return rand.Reader
+	}
+	return newDeterministicReader(opt.Seed)
+}
+
+// zeroReader is an endless stream of zero bytes.
+type zeroReader struct{}
+
+// Read fills p with zeros.
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// newDeterministicReader returns an endless pseudorandom stream fully determined
+// by seed. HKDF-SHA256 expands the seed into an AES-256 key and IV, and the
+// stream is the AES-CTR keystream.
+func newDeterministicReader(seed []byte) io.Reader {
+	material := make([]byte, 32+aes.BlockSize)
+	kdf := hkdf.New(sha256.New, seed, nil, []byte(seedInfo))
+	if _, err := io.ReadFull(kdf, material); err != nil {
+		// HKDF-SHA256 can produce up to 8160 bytes; reading 48 cannot fail.
+		panic(err)
+	}
+	block, err := aes.NewCipher(material[:32])
+	if err != nil {
+		// The key is always 32 bytes long, which AES accepts.
+		panic(err)
+	}
+	stream := cipher.NewCTR(block, material[32:])
+	return cipher.StreamReader{S: stream, R: zeroReader{}}
+}
@@ -0,0 +1,63 @@
+package generator
+
+import "testing"
+
+// TestGeneratePassword_Seed checks that the same seed reproduces the same batch and
+// that a different seed produces a different one.
+func TestGeneratePassword_Seed(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           5,
+		Seed:            []byte("fixture seed"),
+	}
+	first, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range first {
+		if first[i].Value != second[i].Value {
+			t.Errorf("password %d differs between runs: %s vs %s", i, first[i].Value, second[i].Value)
+		}
+	}
+
+	opt.Seed = []byte("another seed")
+	other, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other[0].Value == first[0].Value {
+		t.Error("expected a different seed to produce a different password")
+	}
+}
+
+// TestGeneratePassword_SeedGolden pins the output of a fixed seed, so a change
+// to the sampling of deterministic sources cannot silently alter reproducible
+// passwords.
+func TestGeneratePassword_SeedGolden(t *testing.T) {
+	passwords, err := GeneratePassword(PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+		Count:           3,
+		Seed:            []byte("golden seed"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"y3TLU2[|G{g>&(eB", ".hiMA*@rv+$r*z0Q", "YmZx$Ne14s7tgvV:"}
+	for i, p := range passwords {
+		if p.Value != want[i] {
+			t.Errorf("password %d = %q, want %q", i, p.Value, want[i])
+		}
+	}
+}
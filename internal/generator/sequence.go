@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"math"
+	"strings"
+)
+
+// sequentialRunLength is the shortest run reported by sequentialRun.
+const sequentialRunLength = 3
+
+// orderedSets lists the character sets with a natural order in which runs are
+// detected. Special characters have no natural order and never form a run.
+var orderedSets = [...]string{uppercase, lowercase, numbers}
+
+// orderedPos returns the index of the ordered set containing r and the
+// position of r within it, or -1 and -1 if r is in none of them.
+func orderedPos(r rune) (int, int) {
+	for set, chars := range orderedSets {
+		if i := strings.IndexRune(chars, r); i >= 0 {
+			return set, i
+		}
+	}
+	return -1, -1
+}
+
+// sequentialRun returns the first ascending or descending run of at least
+// sequentialRunLength consecutive characters in s, such as "abc", "XYZ" or
+// "321", or "" if there is none. A run stays within one set: each character is
+// the successor (or each the predecessor) of the one before it in A-Z, a-z or
+// 0-9. Runs never wrap around and never cross sets, so "aBc", "9ab" and "yza"
+// are not sequential.
+func sequentialRun(s string) string {
+	runes := []rune(s)
+	start, step := 0, 0
+	for i := 1; i < len(runes); i++ {
+		prevSet, prevPos := orderedPos(runes[i-1])
+		set, pos := orderedPos(runes[i])
+		d := pos - prevPos
+		if set < 0 || set != prevSet || (d != 1 && d != -1) {
+			start, step = i, 0
+			continue
+		}
+		if d != step {
+			start, step = i-1, d
+		}
+		if i-start+1 >= sequentialRunLength {
+			end := i + 1
+			for end < len(runes) {
+				nextSet, nextPos := orderedPos(runes[end])
+				if nextSet != set || nextPos-pos != step {
+					break
+				}
+				pos = nextPos
+				end++
+			}
+			return string(runes[start:end])
+		}
+	}
+	return ""
+}
+
+// repeatedBigram returns the first two-character sequence that occurs more than
+// once in s, counting overlapping occurrences such as the two "aa" in "aaa", or
+// "" if every bigram is unique.
+func repeatedBigram(s string) string {
+	runes := []rune(s)
+	seen := make(map[[2]rune]struct{}, len(runes))
+	for i := 1; i < len(runes); i++ {
+		bigram := [2]rune{runes[i-1], runes[i]}
+		if _, ok := seen[bigram]; ok {
+			return string(bigram[:])
+		}
+		seen[bigram] = struct{}{}
+	}
+	return ""
+}
+
+// bigramSafety is how many times over maxAttempts must cover the expected
+// number of candidates for a password without repeated bigrams: a password
+// then fails to generate with a probability of about e^-bigramSafety.
+const bigramSafety = 20
+
+// maxBigramLength returns the longest password over a pool of size characters
+// that can be generated without repeated bigrams within maxAttempts. Each of
+// the n(n-1)/2 pairs of the n bigrams of a random password matches with
+// probability 1/size², so the number of repeats is about Poisson distributed
+// and a candidate has none with probability e^-(n(n-1)/2/size²).
+func maxBigramLength(size int) int {
+	limit := math.Log(maxAttempts/bigramSafety) * float64(size) * float64(size)
+	length := 1
+	for n := 1; float64(n)*float64(n-1)/2 <= limit && n <= size*size; n++ {
+		length = n + 1
+	}
+	return length
+}
@@ -0,0 +1,110 @@
+package generator
+
+import "testing"
+
+// TestSequentialRun checks run detection within and across character sets.
+func TestSequentialRun(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"xabcx", "abc"},
+		{"x!321", "321"},
+		{"QXYZW", "XYZ"},
+		{"k1234", "1234"},
+		{"abdcba", "dcba"},
+		{"ab", ""},
+		{"abab", ""},
+		{"aBc", ""},
+		{"9ab", ""},
+		{"yza", ""},
+		{"aab", ""},
+		{"!#$%", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sequentialRun(tt.s); got != tt.want {
+			t.Errorf("sequentialRun(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratePassword_NoSequential checks that no generated password contains
+// a sequential run.
+func TestGeneratePassword_NoSequential(t *testing.T) {
+	opt := PasswordOptions{
+		Length:       20,
+		UseNumbers:   true,
+		UseLower:     true,
+		Count:        300,
+		NoSequential: true,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if run := sequentialRun(gp.Value); run != "" {
+			t.Errorf("password %s contains the sequential run %q", gp.Value, run)
+		}
+	}
+}
+
+// TestRepeatedBigram checks detection of repeated and overlapping bigrams.
+func TestRepeatedBigram(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"abxab", "ab"},
+		{"aaa", "aa"},
+		{"abcabd", "ab"},
+		{"abba", ""},
+		{"aba", ""},
+		{"a", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := repeatedBigram(tt.s); got != tt.want {
+			t.Errorf("repeatedBigram(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratePassword_NoRepeatedBigrams checks that no generated password
+// repeats a bigram, that the longest length validation accepts still generates
+// reliably, and that longer ones are rejected.
+func TestGeneratePassword_NoRepeatedBigrams(t *testing.T) {
+	opt := PasswordOptions{
+		Length:            20,
+		UseNumbers:        true,
+		Count:             100,
+		NoRepeatedBigrams: true,
+	}
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, gp := range passwords {
+		if bigram := repeatedBigram(gp.Value); bigram != "" {
+			t.Errorf("password %s repeats the bigram %q", gp.Value, bigram)
+		}
+	}
+
+	opt.Length = maxBigramLength(10)
+	opt.Count = 50
+	passwords, err = GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error at length %d: %v", opt.Length, err)
+	}
+	for _, gp := range passwords {
+		if bigram := repeatedBigram(gp.Value); bigram != "" {
+			t.Errorf("password %s repeats the bigram %q", gp.Value, bigram)
+		}
+	}
+
+	for _, length := range []int{maxBigramLength(10) + 1, 60, 102} {
+		opt.Length = length
+		if err := ValidateOptions(opt); err == nil {
+			t.Errorf("expected length %d to be rejected for 10 digits", length)
+		}
+	}
+}
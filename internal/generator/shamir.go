@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"errors"
+
+	"github.com/hashicorp/vault/shamir"
+)
+
+// SplitSecret splits password into shares using Shamir's Secret Sharing over
+// GF(2^8), as implemented by HashiCorp Vault's shamir package, so that any
+// threshold of the shares reconstruct it with CombineShares while fewer reveal
+// nothing about it. Each byte of the password is the constant term of its own
+// random polynomial of degree threshold-1. A share holds one evaluation per
+// byte followed by its random, distinct x-coordinate, so it is one byte longer
+// than the password.
+// It requires 2 <= threshold <= shares <= 255 and a non-empty password.
+func SplitSecret(password string, shares, threshold int) ([][]byte, error) {
+	return shamir.Split([]byte(password), shares, threshold)
+}
+
+// CombineShares reconstructs a password from shares produced by SplitSecret by
+// Lagrange interpolation at zero. Given fewer shares than the threshold used to
+// split, it returns an unrelated value rather than an error: the shares carry
+// no record of the threshold.
+func CombineShares(shares [][]byte) (string, error) {
+	if len(shares) < 2 {
+		return "", errors.New("at least two shares are required")
+	}
+	size := len(shares[0])
+	if size < 2 {
+		return "", errors.New("shares are too short")
+	}
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size {
+			return "", errors.New("shares must all have the same length")
+		}
+		// shamir.Combine panics on a zero coordinate, which no share from
+		// SplitSecret has.
+		x := share[size-1]
+		if x == 0 || seen[x] {
+			return "", errors.New("shares must have distinct, non-zero coordinates")
+		}
+		seen[x] = true
+	}
+	secret, err := shamir.Combine(shares)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// shamirVectors are shares of "Go!" with threshold 3 and x-coordinates 1, 2
+// and 3, computed by hand from the coefficients (0x11, 0x22), (0x33, 0x44) and
+// (0xa5, 0x5a) of its bytes over GF(2^8) with the AES polynomial.
+var shamirVectors = []string{"7418de01", "ed020302", "de75fc03"}
+
+// TestCombineShares_KnownAnswer checks that combining shamirVectors, in any
+// order, gives back the secret.
+func TestCombineShares_KnownAnswer(t *testing.T) {
+	vectors := make([][]byte, len(shamirVectors))
+	for i, v := range shamirVectors {
+		vectors[i], _ = hex.DecodeString(v)
+	}
+	for _, order := range [][]int{{0, 1, 2}, {2, 0, 1}, {1, 2, 0}} {
+		got, err := CombineShares([][]byte{vectors[order[0]], vectors[order[1]], vectors[order[2]]})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "Go!" {
+			t.Errorf("CombineShares in order %v = %q, want %q", order, got, "Go!")
+		}
+	}
+}
+
+// TestSplitSecret checks that any threshold shares reconstruct the password and
+// fewer do not.
+func TestSplitSecret(t *testing.T) {
+	password := "x8#Kq2!vLm9@pä🔑"
+	shares, err := SplitSecret(password, 5, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(shares) != 5 || len(shares[0]) != len(password)+1 {
+		t.Fatalf("expected 5 shares of %d bytes, got %d of %d", len(password)+1, len(shares), len(shares[0]))
+	}
+
+	// Every subset of exactly three shares, in any order of selection.
+	for a := range shares {
+		for b := a + 1; b < len(shares); b++ {
+			for c := b + 1; c < len(shares); c++ {
+				got, err := CombineShares([][]byte{shares[c], shares[a], shares[b]})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != password {
+					t.Errorf("shares %d, %d, %d reconstructed %q", a, b, c, got)
+				}
+			}
+		}
+	}
+	if got, err := CombineShares(shares); err != nil || got != password {
+		t.Errorf("all shares reconstructed %q (%v)", got, err)
+	}
+	for a := range shares {
+		for b := a + 1; b < len(shares); b++ {
+			if got, _ := CombineShares([][]byte{shares[a], shares[b]}); got == password {
+				t.Errorf("two shares %d and %d reconstructed the password", a, b)
+			}
+		}
+	}
+}
+
+// TestSplitSecret_Invalid checks the argument and share validation.
+func TestSplitSecret_Invalid(t *testing.T) {
+	for _, tt := range []struct{ shares, threshold int }{{3, 1}, {2, 3}, {256, 2}} {
+		if _, err := SplitSecret("secret", tt.shares, tt.threshold); err == nil {
+			t.Errorf("expected error for %d shares with threshold %d", tt.shares, tt.threshold)
+		}
+	}
+	if _, err := SplitSecret("", 3, 2); err == nil {
+		t.Error("expected error for an empty secret")
+	}
+
+	shares, err := SplitSecret("secret", 3, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	invalid := [][][]byte{
+		shares[:1],
+		{shares[0], shares[0]},
+		{shares[0], shares[1][:3]},
+		{shares[0], append(shares[1][:len(shares[1])-1:len(shares[1])-1], 0)},
+	}
+	for i, set := range invalid {
+		if _, err := CombineShares(set); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key derivation parameters for GenerateSitePassword. Changing any of them
+// changes every site password, so they are fixed for the v1 scheme.
+const (
+	siteContext   = "go-passwordgen site password v1"
+	siteScryptN   = 1 << 15
+	siteScryptR   = 8
+	siteScryptP   = 1
+	siteKeyLength = 32
+)
+
+// GenerateSitePassword derives a password for a login on a site from a master
+// password, in the style of stateless managers like LessPass: the same inputs
+// and options always produce the same password, so nothing needs to be stored.
+//
+// scrypt (N=2^15, r=8, p=1) stretches master with site and login as the salt,
+// and the derived key seeds generation (see PasswordOptions.Seed), so every
+// constraint in opt, including the class requirements, is honored. opt.Count
+// is ignored. Site and login are used exactly as given, so "Example.com" and
+// "example.com" yield different passwords; normalize them first if needed.
+//
+// Security model: anyone who learns master can recompute every site password,
+// and a leaked site password lets an attacker test master guesses offline,
+// slowed only by scrypt, so master must be long and unique. A site password
+// can only be rotated by changing master, site, login or opt. DateToken is
+// rejected because it would break determinism.
+func GenerateSitePassword(master, site, login string, opt PasswordOptions) (GeneratedPassword, error) {
+	if master == "" {
+		return GeneratedPassword{}, errors.New("master password cannot be empty")
+	}
+	if site == "" {
+		return GeneratedPassword{}, errors.New("site cannot be empty")
+	}
+	if opt.DateToken != "" {
+		return GeneratedPassword{}, errors.New("site passwords cannot use a date token")
+	}
+
+	// Length-prefix each part so that no two (site, login) pairs share a salt.
+	salt := []byte(siteContext)
+	for _, part := range []string{site, login} {
+		salt = binary.BigEndian.AppendUint32(salt, uint32(len(part)))
+		salt = append(salt, part...)
+	}
+	key, err := scrypt.Key([]byte(master), salt, siteScryptN, siteScryptR, siteScryptP, siteKeyLength)
+	if err != nil {
+		return GeneratedPassword{}, err
+	}
+	opt.Seed = key
+	opt.Count = 1
+	passwords, err := GeneratePassword(opt)
+	if err != nil {
+		return GeneratedPassword{}, err
+	}
+	return passwords[0], nil
+}
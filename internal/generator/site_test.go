@@ -0,0 +1,76 @@
+package generator
+
+import "testing"
+
+// TestGenerateSitePassword checks that site passwords are deterministic, differ
+// per site and login, and meet the class requirements.
+func TestGenerateSitePassword(t *testing.T) {
+	opt := PasswordOptions{
+		Length:          16,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+	}
+	first, err := GenerateSitePassword("correct horse battery staple", "example.com", "alice", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := GenerateSitePassword("correct horse battery staple", "example.com", "alice", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Value != again.Value {
+		t.Errorf("expected the same password twice, got %s and %s", first.Value, again.Value)
+	}
+	if len(first.Value) != 16 {
+		t.Errorf("expected 16 characters, got %q", first.Value)
+	}
+	for _, set := range []string{specialChars, numbers, uppercase, lowercase} {
+		if !containsAny(first.Value, set) {
+			t.Errorf("password %s has no character from %q", first.Value, set)
+		}
+	}
+
+	others := [][3]string{
+		{"correct horse battery stapler", "example.com", "alice"},
+		{"correct horse battery staple", "example.org", "alice"},
+		{"correct horse battery staple", "example.com", "bob"},
+		{"correct horse battery staple", "example.coma", "lice"}, // same concatenation
+	}
+	for _, o := range others {
+		gp, err := GenerateSitePassword(o[0], o[1], o[2], opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gp.Value == first.Value {
+			t.Errorf("inputs %q produced the same password as the original", o)
+		}
+	}
+
+	if _, err := GenerateSitePassword("", "example.com", "alice", opt); err == nil {
+		t.Error("expected error for an empty master password")
+	}
+	opt.DateToken = "%Y"
+	if _, err := GenerateSitePassword("master", "example.com", "alice", opt); err == nil {
+		t.Error("expected error for a date token")
+	}
+}
+
+// TestGenerateSitePassword_Golden pins a v1 site password, which users rely on
+// recomputing identically across versions.
+func TestGenerateSitePassword_Golden(t *testing.T) {
+	gp, err := GenerateSitePassword("correct horse battery staple", "example.com", "alice", PasswordOptions{
+		Length:          20,
+		UseSpecialChars: true,
+		UseNumbers:      true,
+		UseUpper:        true,
+		UseLower:        true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "xdGv6vZl^6<9S@9j0<2H"; gp.Value != want {
+		t.Errorf("got %q, want %q", gp.Value, want)
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stats summarizes a generation run.
+type Stats struct {
+	Count   int           // Number of passwords generated
+	Elapsed time.Duration // Time taken to generate them
+}
+
+// Throughput returns the number of passwords generated per second,
+// or 0 if no time has elapsed.
+func (s Stats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Count) / s.Elapsed.Seconds()
+}
+
+// Calibration workload for EstimateDuration.
+const (
+	calibrationCount  = 200
+	calibrationLength = 16
+)
+
+var (
+	calibrateOnce sync.Once
+	perRuneCost   time.Duration // Measured cost of generating one password rune
+)
+
+// calibrate measures the cost of generating a single password rune by timing a
+// small batch with every character set enabled.
+func calibrate() time.Duration {
+	calibrateOnce.Do(func() {
+		start := time.Now()
+		// The options are valid, so the result is not needed.
+		_, _ = GeneratePassword(PasswordOptions{
+			Length:          calibrationLength,
+			UseSpecialChars: true,
+			UseNumbers:      true,
+			UseUpper:        true,
+			UseLower:        true,
+			Count:           calibrationCount,
+		})
+		perRuneCost = max(time.Since(start)/(calibrationCount*calibrationLength), time.Nanosecond)
+	})
+	return perRuneCost
+}
+
+// EstimateDuration estimates how long GeneratePassword will take for opt, so a
+// UI can warn before a large batch. The estimate scales a per-character cost,
+// measured once on first use, by Length and Count. It ignores regeneration
+// caused by constraints such as policies or MinDistinct, so treat it as a rough
+// lower bound rather than a guarantee.
+func EstimateDuration(opt PasswordOptions) time.Duration {
+	return calibrate() * time.Duration(max(opt.Length, 1)) * time.Duration(max(opt.Count, 1))
+}
+
+// CountDistinct returns the number of distinct values among passwords. A result
+// below len(passwords) means the batch contains duplicates, a sign that the
+// configured keyspace is too small for the batch size.
+func CountDistinct(passwords []GeneratedPassword) int {
+	seen := make(map[string]struct{}, len(passwords))
+	for _, p := range passwords {
+		seen[p.Value] = struct{}{}
+	}
+	return len(seen)
+}
+
+// HistogramBucket counts the passwords whose entropy falls in [Min, Max), or
+// [Min, Max] for the last bucket of a histogram.
+type HistogramBucket struct {
+	Min, Max float64 // Entropy range in bits
+	Count    int     // Number of passwords in the range
+}
+
+// EntropyHistogram sorts passwords into n equal-width buckets by their
+// effective entropy as computed by AnalyzePassword, so penalized passwords fall
+// below the rest of a batch. The buckets span the lowest to the highest
+// entropy; if every password has the same entropy, a single bucket is returned.
+func EntropyHistogram(passwords []GeneratedPassword, n int) ([]HistogramBucket, error) {
+	if n < 1 {
+		return nil, errors.New("bucket count must be greater than 0")
+	}
+	if len(passwords) == 0 {
+		return nil, nil
+	}
+	entropies := make([]float64, len(passwords))
+	for i, p := range passwords {
+		a, err := AnalyzePassword(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		entropies[i] = a.Entropy
+	}
+	lo, hi := slices.Min(entropies), slices.Max(entropies)
+	if lo == hi {
+		return []HistogramBucket{{Min: lo, Max: hi, Count: len(entropies)}}, nil
+	}
+
+	width := (hi - lo) / float64(n)
+	buckets := make([]HistogramBucket, n)
+	for i := range buckets {
+		buckets[i].Min = lo + float64(i)*width
+		buckets[i].Max = lo + float64(i+1)*width
+	}
+	buckets[n-1].Max = hi
+	for _, e := range entropies {
+		buckets[min(int((e-lo)/width), n-1)].Count++
+	}
+	return buckets, nil
+}
+
+// SortByEntropy sorts passwords in place by descending entropy, keeping the
+// original order among equal entropies, so the strongest come first when a
+// list mixes lengths or configurations.
+func SortByEntropy(passwords []GeneratedPassword) {
+	slices.SortStableFunc(passwords, func(a, b GeneratedPassword) int {
+		return cmp.Compare(b.Entropy, a.Entropy)
+	})
+}
+
+// strengthOrder lists the default strength labels from weakest to strongest.
+var strengthOrder = []string{"Weak", "Moderate", "Strong", "Excellent"}
+
+// FilterByStrength returns the passwords whose Strength label ranks at least
+// minimum, in their original order, and the number left out. minimum must be
+// one of the default labels; passwords labelled by a custom classifier with
+// anything else are left out.
+func FilterByStrength(passwords []GeneratedPassword, minimum string) ([]GeneratedPassword, int, error) {
+	floor := slices.Index(strengthOrder, minimum)
+	if floor < 0 {
+		return nil, 0, fmt.Errorf("unknown strength %q (want one of %s)", minimum, strings.Join(strengthOrder, ", "))
+	}
+	var kept []GeneratedPassword
+	for _, p := range passwords {
+		if slices.Index(strengthOrder, p.Strength) >= floor {
+			kept = append(kept, p)
+		}
+	}
+	return kept, len(passwords) - len(kept), nil
+}
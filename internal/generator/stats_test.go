@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStats_Throughput checks that throughput is count over elapsed seconds.
+func TestStats_Throughput(t *testing.T) {
+	s := Stats{Count: 500, Elapsed: 250 * time.Millisecond}
+	if got := s.Throughput(); got != 2000 {
+		t.Errorf("expected 2000 passwords/s, got %f", got)
+	}
+	if got := (Stats{Count: 10}).Throughput(); got != 0 {
+		t.Errorf("expected 0 for zero elapsed time, got %f", got)
+	}
+
+	start := time.Now()
+	passwords, err := GeneratePassword(PasswordOptions{Length: 16, UseLower: true, UseNumbers: true, Count: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s = Stats{Count: len(passwords), Elapsed: time.Since(start)}
+	if s.Throughput() <= 0 {
+		t.Errorf("expected positive throughput, got %f", s.Throughput())
+	}
+}
+
+// TestEstimateDuration checks that the estimate is positive and scales linearly with Count.
+func TestEstimateDuration(t *testing.T) {
+	opt := PasswordOptions{Length: 16, UseLower: true, UseNumbers: true, Count: 100}
+	small := EstimateDuration(opt)
+	if small <= 0 {
+		t.Fatalf("expected a positive estimate, got %s", small)
+	}
+	opt.Count = 10000
+	large := EstimateDuration(opt)
+	if ratio := float64(large) / float64(small); ratio < 95 || ratio > 105 {
+		t.Errorf("expected the estimate to grow ~100x with Count, got %.1fx (%s vs %s)", ratio, small, large)
+	}
+}
+
+// TestCountDistinct checks that duplicates are counted once.
+func TestCountDistinct(t *testing.T) {
+	passwords := []GeneratedPassword{{Value: "a"}, {Value: "b"}, {Value: "a"}}
+	if got := CountDistinct(passwords); got != 2 {
+		t.Errorf("expected 2 distinct passwords, got %d", got)
+	}
+	if got := CountDistinct(nil); got != 0 {
+		t.Errorf("expected 0 for an empty batch, got %d", got)
+	}
+}
+
+// TestEntropyHistogram checks that the buckets cover the batch and sum to its size.
+func TestEntropyHistogram(t *testing.T) {
+	passwords := []GeneratedPassword{{Value: "abcabcabc"}, {Value: "k3#Lp9!Qz"}, {Value: "hunter2"}}
+	for i := range 20 {
+		passwords = append(passwords, GeneratedPassword{Value: strings.Repeat("x", i+1) + "Y7!"})
+	}
+	buckets, err := EntropyHistogram(passwords, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 5 {
+		t.Fatalf("expected 5 buckets, got %d", len(buckets))
+	}
+	total := 0
+	for i, b := range buckets {
+		total += b.Count
+		if i > 0 && b.Min != buckets[i-1].Max {
+			t.Errorf("bucket %d starts at %.2f, previous ends at %.2f", i, b.Min, buckets[i-1].Max)
+		}
+	}
+	if total != len(passwords) {
+		t.Errorf("expected buckets to sum to %d, got %d", len(passwords), total)
+	}
+
+	buckets, err = EntropyHistogram(passwords[1:2], 5)
+	if err != nil || len(buckets) != 1 || buckets[0].Count != 1 {
+		t.Errorf("expected a single bucket for one password, got %+v (%v)", buckets, err)
+	}
+	if _, err := EntropyHistogram(passwords, 0); err == nil {
+		t.Error("expected error for zero buckets")
+	}
+}
+
+// TestSortByEntropy checks that passwords of varied lengths end up in
+// descending entropy order, and that the option leaves a uniform batch as is.
+func TestSortByEntropy(t *testing.T) {
+	var passwords []GeneratedPassword
+	for _, length := range []int{8, 20, 12} {
+		batch, err := GeneratePassword(PasswordOptions{Length: length, UseLower: true, UseNumbers: true, Count: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		passwords = append(passwords, batch...)
+	}
+	SortByEntropy(passwords)
+	for i, p := range passwords {
+		if want := []int{20, 12, 8}[i/3]; len(p.Value) != want {
+			t.Errorf("expected password %d to have %d characters, got %q", i, want, p.Value)
+		}
+		if i > 0 && p.Entropy > passwords[i-1].Entropy {
+			t.Errorf("password %d has more entropy than the one before it", i)
+		}
+	}
+
+	opt := PasswordOptions{
+		Length:   12,
+		UseLower: true,
+		Count:    10,
+		Seed:     []byte("sort seed"),
+	}
+	generated, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opt.SortByEntropy = true
+	batch, err := GeneratePassword(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, p := range batch {
+		if p.Value != generated[i].Value {
+			t.Errorf("expected a batch of equal entropies to keep its order, got %q at %d", p.Value, i)
+		}
+	}
+}
+
+// TestFilterByStrength checks that only passwords at or above the minimum label
+// are kept, in order, and that an unknown label is rejected.
+func TestFilterByStrength(t *testing.T) {
+	passwords := []GeneratedPassword{
+		{Value: "a", Strength: "Weak"},
+		{Value: "b", Strength: "Excellent"},
+		{Value: "c", Strength: "Moderate"},
+		{Value: "d", Strength: "Strong"},
+	}
+	kept, dropped, err := FilterByStrength(passwords, "Strong")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kept) != 2 || kept[0].Value != "b" || kept[1].Value != "d" {
+		t.Errorf("expected b and d to be kept, got %v", kept)
+	}
+	if dropped != 2 {
+		t.Errorf("expected 2 passwords to be filtered, got %d", dropped)
+	}
+	if _, _, err := FilterByStrength(passwords, "strong"); err == nil {
+		t.Error("expected an error for an unknown strength label")
+	}
+}
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Bundled story word lists, 32 words each (5 bits per slot).
+var (
+	storyAdjectives = []string{
+		"Red", "Blue", "Green", "Gold", "Silver", "Brave", "Calm", "Quick",
+		"Quiet", "Happy", "Lucky", "Bold", "Wild", "Shy", "Proud", "Gentle",
+		"Clever", "Sleepy", "Fuzzy", "Tiny", "Giant", "Swift", "Jolly", "Noble",
+		"Rusty", "Sunny", "Windy", "Frosty", "Misty", "Dusty", "Sly", "Merry",
+	}
+	storyNouns = []string{
+		"Fox", "Bear", "Wolf", "Owl", "Hawk", "Otter", "Tiger", "Lion",
+		"Panda", "Koala", "Moose", "Eagle", "Raven", "Badger", "Beaver", "Camel",
+		"Falcon", "Gecko", "Horse", "Lemur", "Llama", "Mole", "Newt", "Parrot",
+		"Rabbit", "Salmon", "Seal", "Shark", "Sloth", "Swan", "Toad", "Whale",
+	}
+	storyVerbs = []string{
+		"Jumps", "Runs", "Sings", "Dances", "Swims", "Flies", "Reads", "Writes",
+		"Paints", "Climbs", "Dives", "Hides", "Jogs", "Laughs", "Naps", "Plays",
+		"Rides", "Roars", "Sails", "Skates", "Sleeps", "Smiles", "Sneezes", "Spins",
+		"Surfs", "Waves", "Whistles", "Wins", "Yawns", "Bakes", "Cooks", "Dreams",
+	}
+)
+
+// storySlotNames names the word slots of a story, in order.
+var storySlotNames = [...]string{"adjective", "noun", "verb"}
+
+// StoryOptions defines the options for story password generation.
+// Nil word lists select the bundled lists.
+type StoryOptions struct {
+	Adjectives []string `json:"-"`      // Words for the first slot
+	Nouns      []string `json:"-"`      // Words for the second slot
+	Verbs      []string `json:"-"`      // Words for the third slot
+	Digits     int      `json:"digits"` // Number of random digits appended after the verb
+	Symbol     bool     `json:"symbol"` // Append a random special character at the end
+	Count      int      `json:"count"`  // Number of passwords to generate
+}
+
+// slots returns the word list of each slot of a story, in order.
+func (opt StoryOptions) slots() [][]string {
+	pick := func(list, bundled []string) []string {
+		if list == nil {
+			return bundled
+		}
+		return list
+	}
+	return [][]string{
+		pick(opt.Adjectives, storyAdjectives),
+		pick(opt.Nouns, storyNouns),
+		pick(opt.Verbs, storyVerbs),
+	}
+}
+
+// StoryEntropy returns the entropy in bits of a story password generated with
+// opt: the sum of log2(size) over the word slots, plus log2(10) per digit and
+// log2 of the special set for the symbol.
+func StoryEntropy(opt StoryOptions) (float64, error) {
+	if opt.Count < 1 {
+		return 0, errors.New("count must be greater than 0")
+	}
+	if opt.Digits < 0 {
+		return 0, errors.New("digits cannot be negative")
+	}
+	var entropy float64
+	for i, list := range opt.slots() {
+		if err := validateWordlist(list); err != nil {
+			return 0, fmt.Errorf("%s %w", storySlotNames[i], err)
+		}
+		entropy += math.Log2(float64(len(list)))
+	}
+	entropy += float64(opt.Digits) * math.Log2(float64(len(numbers)))
+	if opt.Symbol {
+		entropy += math.Log2(float64(len(specialChars)))
+	}
+	return entropy, nil
+}
+
+// GenerateStory generates opt.Count memorable passwords from a small grammar:
+// adjective + noun + verb, followed by opt.Digits digits and, with opt.Symbol,
+// a special character, e.g. "RedFoxJumps7!". Each slot is drawn uniformly and
+// independently. The words are guessable by design, so use enough digits or
+// long custom lists when the password needs real strength.
+func GenerateStory(opt StoryOptions) ([]GeneratedPassword, error) {
+	entropy, err := StoryEntropy(opt)
+	if err != nil {
+		return nil, err
+	}
+	slots := opt.slots()
+
+	passwords := make([]GeneratedPassword, opt.Count)
+	for i := range passwords {
+		var value strings.Builder
+		for _, list := range slots {
+			n, err := secureRandomInt(len(list))
+			if err != nil {
+				return nil, err
+			}
+			value.WriteString(list[n])
+		}
+		for range opt.Digits {
+			n, err := secureRandomInt(len(numbers))
+			if err != nil {
+				return nil, err
+			}
+			value.WriteByte(numbers[n])
+		}
+		if opt.Symbol {
+			n, err := secureRandomInt(len(specialChars))
+			if err != nil {
+				return nil, err
+			}
+			value.WriteByte(specialChars[n])
+		}
+		passwords[i] = GeneratedPassword{
+			Value:    value.String(),
+			Strength: classifyEntropy(entropy),
+			Entropy:  entropy,
+		}
+	}
+	return passwords, nil
+}
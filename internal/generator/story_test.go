@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestStoryWordlists checks that the bundled lists are valid and hold 32 words each.
+func TestStoryWordlists(t *testing.T) {
+	for i, list := range (StoryOptions{}).slots() {
+		if err := validateWordlist(list); err != nil {
+			t.Errorf("%s list: %v", storySlotNames[i], err)
+		}
+		if len(list) != 32 {
+			t.Errorf("%s list has %d words, want 32", storySlotNames[i], len(list))
+		}
+	}
+}
+
+// TestGenerateStory checks the adjective-noun-verb-number structure and that the
+// entropy sums the per-slot log2(size).
+func TestGenerateStory(t *testing.T) {
+	opt := StoryOptions{
+		Adjectives: []string{"Red", "Blue"},
+		Nouns:      []string{"Fox", "Owl", "Cat", "Dog"},
+		Verbs:      []string{"Jumps", "Runs", "Naps", "Sings", "Swims", "Hides", "Reads", "Waves"},
+		Digits:     2,
+		Symbol:     true,
+		Count:      50,
+	}
+	passwords, err := GenerateStory(opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 1 + 2 + 3 + 2*math.Log2(10) + math.Log2(float64(len(specialChars)))
+	for _, gp := range passwords {
+		rest := gp.Value
+		for i, list := range opt.slots() {
+			word := rest[:strings.IndexFunc(rest[1:], func(r rune) bool { return r < 'a' || r > 'z' })+1]
+			if !slices.Contains(list, word) {
+				t.Fatalf("password %s: %s slot %q is not in its list", gp.Value, storySlotNames[i], word)
+			}
+			rest = rest[len(word):]
+		}
+		if len(rest) != 3 || strings.Trim(rest[:2], numbers) != "" || !strings.ContainsRune(specialChars, rune(rest[2])) {
+			t.Errorf("password %s: expected two digits and a symbol after the words, got %q", gp.Value, rest)
+		}
+		if math.Abs(gp.Entropy-want) > 1e-9 {
+			t.Errorf("expected entropy %.2f, got %.2f", want, gp.Entropy)
+		}
+	}
+
+	if e, err := StoryEntropy(StoryOptions{Digits: 1, Count: 1}); err != nil || math.Abs(e-(15+math.Log2(10))) > 1e-9 {
+		t.Errorf("expected %.2f bits with the bundled lists, got %.2f (%v)", 15+math.Log2(10), e, err)
+	}
+	for _, bad := range []StoryOptions{{Count: 0}, {Count: 1, Digits: -1}, {Count: 1, Nouns: []string{"Fox"}}} {
+		if _, err := GenerateStory(bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
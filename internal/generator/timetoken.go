@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// TimeTokenPeriod is the length of the time window a GenerateTimeToken token
+// stays valid for.
+const TimeTokenPeriod = 30 * time.Second
+
+// timeTokenCharset is the alphabet GenerateTimeToken draws from.
+const timeTokenCharset = uppercase + lowercase + numbers
+
+// GenerateTimeToken returns a length-character alphanumeric token derived from
+// secret and the TimeTokenPeriod window containing t, so every call within a
+// window returns the same token and the next window a fresh one. An
+// HMAC-SHA256 of the window number seeds a deterministic stream from which the
+// characters are drawn without modulo bias, the same way as seeded passwords.
+//
+// It is a demo of rotating service tokens in the spirit of TOTP, not an
+// RFC 6238 implementation: authenticator apps will not produce matching codes.
+// It returns "" if length is not positive.
+func GenerateTimeToken(secret []byte, t time.Time, length int) string {
+	if length <= 0 {
+		return ""
+	}
+	var window [8]byte
+	binary.BigEndian.PutUint64(window[:], uint64(t.Unix()/int64(TimeTokenPeriod/time.Second)))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(window[:])
+	src := newDeterministicReader(mac.Sum(nil))
+
+	token := make([]byte, length)
+	for i := range token {
+		n, err := randomInt(src, len(timeTokenCharset))
+		if err != nil {
+			// The deterministic stream is endless and the charset is not empty.
+			panic(err)
+		}
+		token[i] = timeTokenCharset[n]
+	}
+	return string(token)
+}
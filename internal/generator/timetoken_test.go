@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateTimeToken checks that a token is stable within a time window,
+// changes in the next one, and uses only alphanumeric characters.
+func TestGenerateTimeToken(t *testing.T) {
+	secret := []byte("demo secret")
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	token := GenerateTimeToken(secret, start, 8)
+	if want := "WRlknLwc"; token != want {
+		t.Fatalf("got %q, want the golden token %q", token, want)
+	}
+	if strings.Trim(token, timeTokenCharset) != "" {
+		t.Errorf("token %q has characters outside the alphanumeric set", token)
+	}
+	if got := GenerateTimeToken(secret, start.Add(TimeTokenPeriod-time.Second), 8); got != token {
+		t.Errorf("expected the same token within a window, got %q and %q", token, got)
+	}
+	if got := GenerateTimeToken(secret, start.Add(TimeTokenPeriod), 8); got == token {
+		t.Errorf("expected a new token in the next window, got %q again", got)
+	}
+	if got := GenerateTimeToken([]byte("other secret"), start, 8); got == token {
+		t.Errorf("expected a different secret to give a different token, got %q again", got)
+	}
+	if got := GenerateTimeToken(secret, start, 0); got != "" {
+		t.Errorf("expected an empty token for length 0, got %q", got)
+	}
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TokenEncoding selects how GenerateToken encodes its random bytes.
+type TokenEncoding int
+
+const (
+	TokenHex       TokenEncoding = iota // Lowercase hexadecimal
+	TokenBase64URL                      // Unpadded URL-safe base64 (RFC 4648)
+	TokenBase58                         // Base58 with the Bitcoin alphabet, without 0, O, I and l
+)
+
+// TokenEncodings lists every token encoding in display order.
+var TokenEncodings = []TokenEncoding{TokenHex, TokenBase64URL, TokenBase58}
+
+// Base58Alphabet is the Bitcoin base58 alphabet: digits and letters without
+// the easily confused 0, O, I and l.
+const Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// String returns the name of the token encoding.
+func (e TokenEncoding) String() string {
+	switch e {
+	case TokenHex:
+		return "hex"
+	case TokenBase64URL:
+		return "base64url"
+	case TokenBase58:
+		return "base58"
+	default:
+		return fmt.Sprintf("TokenEncoding(%d)", int(e))
+	}
+}
+
+// ParseTokenEncoding returns the token encoding with the given name, as
+// returned by TokenEncoding.String.
+func ParseTokenEncoding(name string) (TokenEncoding, error) {
+	for _, e := range TokenEncodings {
+		if e.String() == name {
+			return e, nil
+		}
+	}
+	names := make([]string, len(TokenEncodings))
+	for i, e := range TokenEncodings {
+		names[i] = e.String()
+	}
+	return 0, fmt.Errorf("unknown token encoding %q (expected %s)", name, strings.Join(names, ", "))
+}
+
+// MaxTokenBytes is the most random bytes a token may have: 8192 bits, far
+// beyond any key size, which keeps a mistyped length from allocating and
+// encoding megabytes.
+const MaxTokenBytes = 1024
+
+// GenerateToken returns byteLen random bytes from crypto/rand in the given
+// encoding, for API keys and IDs. Its entropy is 8 bits per byte whatever the
+// encoding. It returns an error unless byteLen is between 1 and MaxTokenBytes.
+func GenerateToken(byteLen int, enc TokenEncoding) (string, error) {
+	if byteLen < 1 {
+		return "", errors.New("token length must be at least 1 byte")
+	}
+	if byteLen > MaxTokenBytes {
+		return "", fmt.Errorf("token length must be at most %d bytes", MaxTokenBytes)
+	}
+	b := make([]byte, byteLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	switch enc {
+	case TokenHex:
+		return hex.EncodeToString(b), nil
+	case TokenBase64URL:
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case TokenBase58:
+		return encodeBase58(b), nil
+	default:
+		return "", fmt.Errorf("unknown token encoding: %d", int(enc))
+	}
+}
+
+// MinOneTimeTokenBytes is the fewest random bytes a one-time token may have:
+// 128 bits, so a token cannot be guessed while it is valid.
+const MinOneTimeTokenBytes = 16
+
+// NewOneTimeToken returns a URL-safe token of byteLen random bytes for
+// one-time links such as magic sign-in links, and the time it expires: ttl
+// from now. The token is unpadded base64url, so it needs no escaping in a
+// query string or path. Storing the expiry and refusing reuse is up to the
+// caller. It returns an error unless byteLen is between MinOneTimeTokenBytes
+// and MaxTokenBytes and ttl is positive.
+func NewOneTimeToken(byteLen int, ttl time.Duration) (token string, expiresAt time.Time, err error) {
+	if byteLen < MinOneTimeTokenBytes {
+		return "", time.Time{}, fmt.Errorf("token length must be at least %d bytes", MinOneTimeTokenBytes)
+	}
+	if ttl <= 0 {
+		return "", time.Time{}, errors.New("ttl must be positive")
+	}
+	token, err = GenerateToken(byteLen, TokenBase64URL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(ttl), nil
+}
+
+// GenerateOneTimeToken is the original form of NewOneTimeToken, kept for
+// existing callers. Instead of reporting a bad byteLen it silently clamps it:
+// a byteLen below MinOneTimeTokenBytes is raised to it and one above
+// MaxTokenBytes is lowered to it, so the token may not have the length asked
+// for. A ttl that is not positive would yield a token that has already
+// expired, so it panics instead, like time.NewTicker.
+//
+// Deprecated: Use NewOneTimeToken, which returns an error for either mistake.
+func GenerateOneTimeToken(byteLen int, ttl time.Duration) (token string, expiresAt time.Time) {
+	if ttl <= 0 {
+		panic("generator: non-positive ttl for GenerateOneTimeToken")
+	}
+	token, expiresAt, err := NewOneTimeToken(min(max(byteLen, MinOneTimeTokenBytes), MaxTokenBytes), ttl)
+	if err != nil {
+		// crypto/rand.Read does not return errors since Go 1.24.
+		panic(err)
+	}
+	return token, expiresAt
+}
+
+// encodeBase58 encodes b with Base58Alphabet the way Bitcoin does: as a
+// big-endian number, with each leading zero byte written as a leading "1".
+func encodeBase58(b []byte) string {
+	var digits []byte
+	n := new(big.Int).SetBytes(b)
+	radix, mod := big.NewInt(int64(len(Base58Alphabet))), new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		digits = append(digits, Base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		digits = append(digits, Base58Alphabet[0])
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeBase58 reverses encodeBase58.
+func decodeBase58(t *testing.T, s string) []byte {
+	t.Helper()
+	n := new(big.Int)
+	radix := big.NewInt(int64(len(Base58Alphabet)))
+	for _, r := range s {
+		i := strings.IndexRune(Base58Alphabet, r)
+		if i < 0 {
+			t.Fatalf("invalid base58 character %q in %s", r, s)
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(i)))
+	}
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+	return append(make([]byte, zeros), n.Bytes()...)
+}
+
+// TestEncodeBase58 checks known vectors and that random byte strings, including
+// ones with leading zeros, decode back to the same bytes.
+func TestEncodeBase58(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"\x00":         "1",
+		"\x00\x00\x01": "112",
+		"Hello World!": "2NEpo7TZRRrLZSi2U",
+	}
+	for in, want := range tests {
+		if got := encodeBase58([]byte(in)); got != want {
+			t.Errorf("encodeBase58(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	// Up to two leading zero bytes followed by random bytes.
+	for i := range 50 {
+		b := make([]byte, 3+i%20)
+		if _, err := rand.Read(b[i%3:]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := decodeBase58(t, encodeBase58(b)); !bytes.Equal(got, b) {
+			t.Errorf("round trip of %x gave %x", b, got)
+		}
+	}
+}
+
+// TestGenerateToken checks the length and alphabet of each encoding, and that
+// base58 tokens avoid 0, O, I and l and decode to the requested number of bytes.
+func TestGenerateToken(t *testing.T) {
+	for range 100 {
+		token, err := GenerateToken(32, TokenBase58)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.ContainsAny(token, "0OIl") {
+			t.Fatalf("base58 token %s contains an ambiguous character", token)
+		}
+		if got := decodeBase58(t, token); len(got) != 32 {
+			t.Errorf("expected %s to decode to 32 bytes, got %d", token, len(got))
+		}
+	}
+
+	token, err := GenerateToken(16, TokenHex)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := hex.DecodeString(token); err != nil || len(b) != 16 {
+		t.Errorf("expected 16 hex-encoded bytes, got %q", token)
+	}
+	token, err = GenerateToken(16, TokenBase64URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := base64.RawURLEncoding.DecodeString(token); err != nil || len(b) != 16 {
+		t.Errorf("expected 16 base64url-encoded bytes, got %q", token)
+	}
+
+	if _, err := GenerateToken(0, TokenHex); err == nil {
+		t.Error("expected error for a zero length")
+	}
+	if _, err := GenerateToken(MaxTokenBytes, TokenBase58); err != nil {
+		t.Errorf("unexpected error at MaxTokenBytes: %v", err)
+	}
+	if _, err := GenerateToken(MaxTokenBytes+1, TokenHex); err == nil {
+		t.Error("expected error for a length above MaxTokenBytes")
+	}
+	if _, err := ParseTokenEncoding("base32"); err == nil {
+		t.Error("expected error for an unknown encoding")
+	}
+	if e, err := ParseTokenEncoding("base58"); err != nil || e != TokenBase58 {
+		t.Errorf("expected base58, got %v (%v)", e, err)
+	}
+}
+
+// TestGenerateOneTimeToken checks that the token is URL-safe, that it expires
+// ttl from now, that byteLen is clamped and that a non-positive ttl panics.
+func TestGenerateOneTimeToken(t *testing.T) {
+	const ttl = 15 * time.Minute
+	before := time.Now()
+	token, expiresAt := GenerateOneTimeToken(32, ttl)
+	after := time.Now()
+
+	if token == "" || url.QueryEscape(token) != token || url.PathEscape(token) != token {
+		t.Errorf("token %q is not URL-safe", token)
+	}
+	if len(token) != 43 {
+		t.Errorf("expected 43 characters for 32 bytes, got %d", len(token))
+	}
+	if expiresAt.Before(before.Add(ttl)) || expiresAt.After(after.Add(ttl)) {
+		t.Errorf("expected expiry between %v and %v, got %v", before.Add(ttl), after.Add(ttl), expiresAt)
+	}
+
+	for _, n := range []int{-1, 0, 1} {
+		if token, _ := GenerateOneTimeToken(n, ttl); len(token) != 22 {
+			t.Errorf("expected %d bytes to be raised to 16 (22 characters), got %q", n, token)
+		}
+	}
+	if token, _ := GenerateOneTimeToken(MaxTokenBytes+1, ttl); len(token) != base64.RawURLEncoding.EncodedLen(MaxTokenBytes) {
+		t.Errorf("expected %d bytes to be lowered to MaxTokenBytes, got %d characters", MaxTokenBytes+1, len(token))
+	}
+
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for a ttl of %v", ttl)
+				}
+			}()
+			GenerateOneTimeToken(32, ttl)
+		}()
+	}
+}
+
+// TestNewOneTimeToken checks that NewOneTimeToken rejects tokens shorter than
+// MinOneTimeTokenBytes or longer than MaxTokenBytes and a non-positive ttl
+// instead of panicking.
+func TestNewOneTimeToken(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, MinOneTimeTokenBytes - 1, MaxTokenBytes + 1} {
+		if _, _, err := NewOneTimeToken(n, time.Minute); err == nil {
+			t.Errorf("expected error for %d bytes", n)
+		}
+	}
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		if _, _, err := NewOneTimeToken(MinOneTimeTokenBytes, ttl); err == nil {
+			t.Errorf("expected error for a ttl of %v", ttl)
+		}
+	}
+	token, expiresAt, err := NewOneTimeToken(MinOneTimeTokenBytes, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(token) != 22 || !expiresAt.After(time.Now()) {
+		t.Errorf("unexpected token %q expiring at %v", token, expiresAt)
+	}
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minBatchPerWorker is the smallest number of passwords worth an extra worker
+// when PasswordOptions.Workers is 0; below it, starting and coordinating a
+// goroutine costs more than the work it takes over.
+const minBatchPerWorker = 64
+
+// workerCount returns the number of goroutines that generate passwords for opt.
+// Seeded generation uses one, so the output stays reproducible. An explicit
+// opt.Workers is capped at opt.Count. In auto mode (opt.Workers == 0) there is
+// one worker per minBatchPerWorker passwords, capped at runtime.NumCPU(), so
+// small batches are generated sequentially.
+func workerCount(opt PasswordOptions) int {
+	if len(opt.Seed) > 0 {
+		return 1
+	}
+	if opt.Workers > 0 {
+		return min(opt.Workers, opt.Count)
+	}
+	return max(1, min(runtime.NumCPU(), opt.Count/minBatchPerWorker))
+}
+
+// generated is the outcome of generating the password at index in a batch.
+type generated struct {
+	index   int
+	value   string
+	retries int
+	err     error
+}
+
+// generateConcurrently generates opt.Count values with the given number of
+// workers and returns the total number of discarded candidates. opt.Reject is
+// serialized across workers. emit is called on the calling goroutine for each
+// value as it completes; the first error from a worker or from emit stops the
+// batch and is returned.
+func generateConcurrently(opt PasswordOptions, p pools, head, tail string, workers int, emit func(i int, value string) error) (int, error) {
+	if reject := opt.Reject; reject != nil {
+		var mu sync.Mutex
+		opt.Reject = func(value string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return reject(value)
+		}
+	}
+
+	jobs := make(chan int)
+	results := make(chan generated)
+	stop := make(chan struct{})
+	go func() {
+		defer close(jobs)
+		for i := range opt.Count {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			src := randomSource(opt)
+			for i := range jobs {
+				value, retries, err := generateValue(src, opt, p, head, tail)
+				select {
+				case results <- generated{i, value, retries, err}:
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var retries int
+	var firstErr error
+	for r := range results {
+		retries += r.retries
+		if firstErr != nil {
+			continue
+		}
+		if r.err == nil {
+			r.err = emit(r.index, r.value)
+		}
+		if r.err != nil {
+			firstErr = r.err
+			close(stop)
+		}
+	}
+	return retries, firstErr
+}
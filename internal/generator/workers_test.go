@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWorkerCount checks the auto heuristic, the cap on explicit counts and that
+// seeded generation stays sequential.
+func TestWorkerCount(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  PasswordOptions
+		want int
+	}{
+		{"tiny batch", PasswordOptions{Count: 3}, 1},
+		{"below two workers' worth", PasswordOptions{Count: 2*minBatchPerWorker - 1}, 1},
+		{"large batch", PasswordOptions{Count: 1000 * minBatchPerWorker}, runtime.NumCPU()},
+		{"explicit", PasswordOptions{Count: 10, Workers: 4}, 4},
+		{"explicit above count", PasswordOptions{Count: 2, Workers: 8}, 2},
+		{"seeded", PasswordOptions{Count: 10000, Workers: 8, Seed: []byte("seed")}, 1},
+	}
+	for _, tt := range tests {
+		if got := workerCount(tt.opt); got != tt.want {
+			t.Errorf("%s: workerCount = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratePassword_Workers checks that concurrent generation fills every
+// slot exactly once and reports each password to the callbacks.
+func TestGeneratePassword_Workers(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		var generated, rejectCalls int
+		opt := PasswordOptions{
+			Length:   12,
+			UseLower: true,
+			Count:    500,
+			Workers:  workers,
+			Reject: func(string) bool {
+				rejectCalls++ // serialized across workers
+				return false
+			},
+			OnGenerated: func(GeneratedPassword) error {
+				generated++
+				return nil
+			},
+		}
+		if _, err := GeneratePassword(opt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if generated != 500 || rejectCalls != 500 {
+			t.Fatalf("workers=%d: expected 500 callbacks and Reject calls, got %d and %d",
+				workers, generated, rejectCalls)
+		}
+
+		opt.OnGenerated = nil
+		passwords, err := GeneratePassword(opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(passwords) != 500 {
+			t.Fatalf("workers=%d: expected 500 passwords, got %d", workers, len(passwords))
+		}
+		for i, gp := range passwords {
+			if len(gp.Value) != 12 {
+				t.Fatalf("workers=%d: password %d is %q", workers, i, gp.Value)
+			}
+		}
+	}
+
+	errVault := errors.New("vault unavailable")
+	opt := PasswordOptions{
+		Length:      12,
+		UseLower:    true,
+		Count:       500,
+		Workers:     4,
+		OnGenerated: func(GeneratedPassword) error { return errVault },
+	}
+	if _, err := GeneratePassword(opt); !errors.Is(err, errVault) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	opt.Workers = -1
+	if _, err := GeneratePassword(opt); err == nil {
+		t.Error("expected error for negative workers")
+	}
+}
+
+// TestGeneratePassword_Concurrent hammers GeneratePassword from many goroutines
+// sharing one options value while the classifier is swapped, and checks every
+// call returns the requested count.
+// Run it with -race to detect shared mutable state.
+func TestGeneratePassword_Concurrent(t *testing.T) {
+	const goroutines, calls, count = 32, 20, 5
+	opt := PasswordOptions{
+		Length:            16,
+		UseSpecialChars:   true,
+		UseNumbers:        true,
+		UseUpper:          true,
+		UseLower:          true,
+		Count:             count,
+		RequireCategory:   []string{"Ll"},
+		RequireFromGroups: [][]rune{[]rune("!@#")},
+		ExtraChars:        "é",
+		Policies:          []Policy{{Name: "min", MinLength: 12}},
+	}
+	t.Cleanup(func() { SetClassifier(nil) })
+
+	var total atomic.Int64
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range calls {
+				if g == 0 {
+					SetClassifier(func(a Analysis) string { return a.Strength })
+				}
+				passwords, err := GeneratePassword(opt)
+				if err != nil {
+					errs <- err
+					return
+				}
+				for _, gp := range passwords {
+					if len([]rune(gp.Value)) != 16 {
+						t.Errorf("expected 16 characters, got %q", gp.Value)
+					}
+				}
+				total.Add(int64(len(passwords)))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := int64(goroutines * calls * count); total.Load() != want {
+		t.Errorf("expected %d passwords, got %d", want, total.Load())
+	}
+}
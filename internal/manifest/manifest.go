@@ -0,0 +1,163 @@
+// Package manifest writes tamper-evident records of a generation run for
+// compliance auditing.
+//
+// A manifest holds the non-secret options of a run, when it started and
+// finished, and a keyed fingerprint of every generated password, never the
+// passwords themselves. It is signed with an HMAC-SHA256 under a key held by
+// the auditor, so any later change to the file invalidates the signature.
+// The fingerprints are keyed by the same key and a random per-manifest salt,
+// so without the key they cannot be brute-forced even for a short PIN, and the
+// same password gives different fingerprints in different manifests.
+package manifest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Version is the manifest format version written by this package. Version 1
+// stored unkeyed SHA-256 fingerprints.
+const Version = 2
+
+// saltSize is the number of random bytes in a manifest's salt.
+const saltSize = 16
+
+// fingerprintContext separates the fingerprint key from the signing key.
+const fingerprintContext = "go-passwordgen manifest fingerprint v2"
+
+// ErrInvalidSignature is returned by Verify when a manifest is unsigned or its
+// signature does not match its contents.
+var ErrInvalidSignature = errors.New("manifest signature is invalid")
+
+// Manifest records a generation run.
+type Manifest struct {
+	Version      int             `json:"version"`
+	Options      json.RawMessage `json:"options"`      // Generator options as JSON, without secret fields
+	Started      time.Time       `json:"started"`      // When generation started
+	Finished     time.Time       `json:"finished"`     // When generation finished
+	Salt         string          `json:"salt"`         // Hex random salt of the fingerprint key
+	Fingerprints []string        `json:"fingerprints"` // Keyed fingerprint of each generated password, in order
+
+	// Signature is the hex HMAC-SHA256 of the manifest's JSON encoding with an
+	// empty Signature. It is set by Sign.
+	Signature string `json:"signature,omitempty"`
+}
+
+// New returns an unsigned manifest of the current format version for a run
+// with the given options, which are encoded as JSON, and a fresh random salt.
+// It records the fingerprint of each password under key; see Fingerprint.
+// Timestamps are stored in UTC.
+func New(key []byte, options any, started, finished time.Time, passwords []string) (Manifest, error) {
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to encode options: %w", err)
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return Manifest{}, err
+	}
+	m := Manifest{
+		Version:      Version,
+		Options:      raw,
+		Started:      started.UTC(),
+		Finished:     finished.UTC(),
+		Salt:         hex.EncodeToString(salt),
+		Fingerprints: make([]string, len(passwords)),
+	}
+	for i, p := range passwords {
+		if m.Fingerprints[i], err = m.Fingerprint(key, p); err != nil {
+			return Manifest{}, err
+		}
+	}
+	return m, nil
+}
+
+// Fingerprint returns the fingerprint of password in m: the hex HMAC-SHA256 of
+// password under a key derived from key and m's salt. An auditor holding key
+// can check a password against the manifest; nobody else can test guesses.
+func (m Manifest) Fingerprint(key []byte, password string) (string, error) {
+	if len(key) == 0 {
+		return "", errors.New("signing key is empty")
+	}
+	salt, err := hex.DecodeString(m.Salt)
+	if err != nil || len(salt) == 0 {
+		return "", errors.New("manifest salt is missing or invalid")
+	}
+	derive := hmac.New(sha256.New, key)
+	derive.Write([]byte(fingerprintContext))
+	derive.Write(salt)
+	h := hmac.New(sha256.New, derive.Sum(nil))
+	h.Write([]byte(password))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// mac returns the HMAC-SHA256 of m without its signature under key.
+func (m Manifest) mac(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errors.New("signing key is empty")
+	}
+	m.Signature = ""
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// Sign sets the signature of m under key.
+func (m *Manifest) Sign(key []byte) error {
+	sum, err := m.mac(key)
+	if err != nil {
+		return err
+	}
+	m.Signature = hex.EncodeToString(sum)
+	return nil
+}
+
+// Verify returns nil if m carries a valid signature under key, and
+// ErrInvalidSignature if it does not.
+func (m Manifest) Verify(key []byte) error {
+	sum, err := m.mac(key)
+	if err != nil {
+		return err
+	}
+	got, err := hex.DecodeString(m.Signature)
+	if err != nil || !hmac.Equal(got, sum) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Write writes m to path as indented JSON, readable only by its owner.
+func Write(path string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Read reads the manifest at path. It does not verify the signature.
+func Read(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return m, nil
+}
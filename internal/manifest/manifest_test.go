@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestManifest_Verify checks that a signed manifest verifies after a round
+// trip through a file, and that altering any part of it or using another key
+// invalidates the signature.
+func TestManifest_Verify(t *testing.T) {
+	key := []byte("auditor key")
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, err := New(key, map[string]int{"length": 16}, started, started.Add(time.Millisecond), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.Sign(key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := Write(path, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	read, err := Read(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := read.Verify(key); err != nil {
+		t.Fatalf("expected the manifest to verify, got %v", err)
+	}
+
+	alterations := map[string]func(*Manifest){
+		"fingerprint": func(m *Manifest) { m.Fingerprints = []string{m.Fingerprints[0], "ffffffff"} },
+		"salt":        func(m *Manifest) { m.Salt = "00" + m.Salt[2:] },
+		"options":     func(m *Manifest) { m.Options = []byte(`{"length":8}`) },
+		"timestamp":   func(m *Manifest) { m.Finished = m.Finished.Add(time.Hour) },
+		"signature":   func(m *Manifest) { m.Signature = "00" + m.Signature[2:] },
+	}
+	for name, alter := range alterations {
+		altered := read
+		altered.Fingerprints = append([]string(nil), read.Fingerprints...)
+		alter(&altered)
+		if err := altered.Verify(key); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+	if err := read.Verify([]byte("other key")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for another key, got %v", err)
+	}
+	if err := m.Sign(nil); err == nil {
+		t.Error("expected an error for an empty key")
+	}
+}
+
+// TestManifest_Fingerprints checks that fingerprints match their passwords
+// under the key only, so a low-entropy PIN cannot be recovered from the
+// manifest by trying every candidate, and that they differ between manifests.
+func TestManifest_Fingerprints(t *testing.T) {
+	key := []byte("auditor key")
+	const pin = "3541"
+	m, err := New(key, nil, time.Now(), time.Now(), []string{pin})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := m.Fingerprint(key, pin); err != nil || got != m.Fingerprints[0] {
+		t.Errorf("expected the PIN to match its fingerprint under the key, got %q, %v", got, err)
+	}
+
+	other := []byte("attacker key")
+	for n := range 10000 {
+		guess := fmt.Sprintf("%04d", n)
+		sum := sha256.Sum256([]byte(guess))
+		if plain := hex.EncodeToString(sum[:]); plain[:8] == m.Fingerprints[0][:8] {
+			t.Fatalf("unkeyed SHA-256 of %s matches the fingerprint", guess)
+		}
+		if got, _ := m.Fingerprint(other, guess); got == m.Fingerprints[0] {
+			t.Fatalf("guess %s matches the fingerprint without the key", guess)
+		}
+	}
+
+	again, err := New(key, nil, time.Now(), time.Now(), []string{pin})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Salt == m.Salt || again.Fingerprints[0] == m.Fingerprints[0] {
+		t.Error("expected a fresh salt and fingerprint for each manifest")
+	}
+	if _, err := New(nil, nil, time.Now(), time.Now(), []string{pin}); err == nil {
+		t.Error("expected an error for an empty key")
+	}
+}
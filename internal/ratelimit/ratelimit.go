@@ -0,0 +1,98 @@
+// Package ratelimit provides per-client token-bucket rate limiting for the serve command.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter decides whether a request may proceed.
+type Limiter interface {
+	// Allow reports whether a request from the client identified by key may
+	// proceed now, consuming that client's capacity if so.
+	Allow(key string) bool
+}
+
+// TokenBucket holds up to burst tokens and refills at rate tokens per second.
+// Each allowed request takes one token. It is safe for concurrent use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time // Clock, replaced in tests
+}
+
+// NewTokenBucket returns a full TokenBucket that refills at rate tokens per
+// second and holds at most burst tokens (at least 1).
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	b := &TokenBucket{rate: rate, burst: float64(max(burst, 1)), now: time.Now}
+	b.tokens = b.burst
+	b.last = b.now()
+	return b
+}
+
+// Allow reports whether a request may proceed now, taking a token if so.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// PerKey is a Limiter that gives each key its own TokenBucket, so one client
+// using up its requests does not refuse the others. Buckets idle long enough
+// to have refilled are dropped, since a new bucket behaves the same, which
+// keeps memory bounded by the clients seen recently. It is safe for
+// concurrent use.
+type PerKey struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     int
+	buckets   map[string]*TokenBucket
+	lastSweep time.Time
+	now       func() time.Time // Clock, replaced in tests
+}
+
+// NewPerKey returns a PerKey whose buckets refill at rate tokens per second
+// and hold at most burst tokens (at least 1).
+func NewPerKey(rate float64, burst int) *PerKey {
+	l := &PerKey{rate: rate, burst: max(burst, 1), buckets: make(map[string]*TokenBucket), now: time.Now}
+	l.lastSweep = l.now()
+	return l
+}
+
+// Allow implements Limiter.
+func (l *PerKey) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.rate > 0 {
+		refill := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+		if now.Sub(l.lastSweep) >= refill {
+			for k, b := range l.buckets {
+				if now.Sub(b.last) >= refill {
+					delete(l.buckets, k)
+				}
+			}
+			l.lastSweep = now
+		}
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = NewTokenBucket(l.rate, l.burst)
+		b.now = l.now
+		b.last = now
+		l.buckets[key] = b
+	}
+	return b.Allow()
+}
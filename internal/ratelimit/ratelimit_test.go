@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTokenBucket checks that the burst is allowed, further requests are
+// refused, and tokens refill at the configured rate.
+func TestTokenBucket(t *testing.T) {
+	clock := time.Unix(0, 0)
+	b := NewTokenBucket(2, 3)
+	b.now = func() time.Time { return clock }
+	b.last = clock
+
+	for i := range 3 {
+		if !b.Allow() {
+			t.Fatalf("request %d within the burst was refused", i+1)
+		}
+	}
+	if b.Allow() {
+		t.Fatal("expected the request after the burst to be refused")
+	}
+
+	clock = clock.Add(500 * time.Millisecond) // one token at 2/s
+	if !b.Allow() {
+		t.Error("expected a refilled token to be allowed")
+	}
+	if b.Allow() {
+		t.Error("expected only one token to refill")
+	}
+
+	clock = clock.Add(time.Hour) // refill never exceeds the burst
+	for i := range 3 {
+		if !b.Allow() {
+			t.Fatalf("request %d after refilling was refused", i+1)
+		}
+	}
+	if b.Allow() {
+		t.Error("expected the bucket to hold at most the burst")
+	}
+}
+
+// TestPerKey checks that each key has its own bucket and that buckets idle
+// long enough to refill are dropped.
+func TestPerKey(t *testing.T) {
+	clock := time.Unix(0, 0)
+	l := NewPerKey(1, 2)
+	l.now = func() time.Time { return clock }
+	l.lastSweep = clock
+
+	for i := range 2 {
+		if !l.Allow("a") {
+			t.Fatalf("request %d of a within the burst was refused", i+1)
+		}
+	}
+	if l.Allow("a") {
+		t.Fatal("expected a to be refused after its burst")
+	}
+	for i := range 2 {
+		if !l.Allow("b") {
+			t.Errorf("request %d of b was refused because of a", i+1)
+		}
+	}
+
+	clock = clock.Add(2 * time.Second) // both buckets have refilled
+	if !l.Allow("c") {
+		t.Fatal("expected a new key to be allowed")
+	}
+	if len(l.buckets) != 1 {
+		t.Errorf("expected the refilled buckets to be dropped, got %d buckets", len(l.buckets))
+	}
+	if !l.Allow("a") || !l.Allow("a") {
+		t.Error("expected a to have its burst again")
+	}
+}
@@ -0,0 +1,66 @@
+// Package secretstore saves secrets in the operating system's secret store.
+//
+// The backend is selected at build time: the login Keychain (via security) on
+// macOS and the Secret Service (via secret-tool) on Linux and the BSDs. Other
+// platforms get a backend that always returns ErrUnsupported.
+package secretstore
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Service is the service name every secret is stored under.
+const Service = "go-passwordgen"
+
+// ErrUnsupported is returned when no secret store backend is available.
+var ErrUnsupported = errors.New("secret store is not supported on this platform")
+
+// Storer saves a secret under an account name, replacing any existing one.
+type Storer interface {
+	Store(account, secret string) error
+}
+
+// New returns the secret store backend for the current platform.
+func New() Storer {
+	return newPlatform()
+}
+
+// run runs name with args, writing input to its standard input so that the
+// secret never appears in the process list.
+func run(input, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// unsupported is the backend for platforms without a secret store.
+type unsupported struct{}
+
+// Store always returns ErrUnsupported.
+func (unsupported) Store(string, string) error {
+	return ErrUnsupported
+}
+
+// Fake records stored secrets in memory. It is intended for tests.
+type Fake struct {
+	Secrets map[string]string // Stored secrets keyed by account
+	Err     error             // Error returned by Store, if set
+}
+
+// Store records secret under account and returns f.Err.
+func (f *Fake) Store(account, secret string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if f.Secrets == nil {
+		f.Secrets = make(map[string]string)
+	}
+	f.Secrets[account] = secret
+	return nil
+}
@@ -0,0 +1,24 @@
+package secretstore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// keychain stores secrets in the login Keychain.
+type keychain struct{}
+
+// newPlatform returns a backend using the security utility.
+func newPlatform() Storer {
+	return keychain{}
+}
+
+// Store adds or updates a generic password item. The command is sent through
+// security's interactive mode with the secret hex-encoded, so it is neither
+// on the command line nor subject to quoting.
+func (keychain) Store(account, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		strconv.Quote(Service), strconv.Quote(account), hex.EncodeToString([]byte(secret)))
+	return run(command, "security", "-i")
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !linux && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package secretstore
+
+// newPlatform returns a backend that always reports ErrUnsupported.
+func newPlatform() Storer {
+	return unsupported{}
+}
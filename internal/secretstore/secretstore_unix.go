@@ -0,0 +1,24 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly
+
+package secretstore
+
+import "os/exec"
+
+// secretService stores secrets through the Secret Service API.
+type secretService struct{}
+
+// newPlatform returns a backend using secret-tool, or one that reports
+// ErrUnsupported when secret-tool is not installed.
+func newPlatform() Storer {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return unsupported{}
+	}
+	return secretService{}
+}
+
+// Store saves secret with the service and account attributes; secret-tool
+// reads it from standard input and replaces any item with the same attributes.
+func (secretService) Store(account, secret string) error {
+	return run(secret, "secret-tool", "store", "--label="+Service+" ("+account+")",
+		"service", Service, "account", account)
+}
@@ -0,0 +1,199 @@
+// Package statefile records which passwords have already been issued, so that
+// later runs never emit them again.
+//
+// The file stores a random salt on its first line and one HMAC-SHA256 of an
+// issued password, keyed with that salt, on every following line. Passwords
+// are never written in plaintext. Concurrent processes are serialized with a
+// lock file created next to the state file.
+package statefile
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// header starts the first line of every state file, followed by the hex salt.
+const header = "go-passwordgen-state v1 "
+
+const (
+	saltLength   = 32
+	lockInterval = 50 * time.Millisecond
+)
+
+// lockTimeout is how long Open waits for another process to release the lock.
+var lockTimeout = 5 * time.Second
+
+// Store is an open state file. It holds the file's lock until Close is called.
+type Store struct {
+	path    string
+	salt    []byte
+	seen    map[string]bool
+	pending []string // Digests added since Open, not yet written
+	created bool     // The file did not exist and needs a header
+}
+
+// Open locks and loads the state file at path, creating it on the first Close
+// if it does not exist yet. It waits up to five seconds for another process to
+// release the lock.
+func Open(path string) (*Store, error) {
+	if err := lock(path); err != nil {
+		return nil, err
+	}
+	s, err := load(path)
+	if err != nil {
+		unlock(path)
+		return nil, err
+	}
+	return s, nil
+}
+
+// lockPath returns the path of the lock file guarding path.
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+// lock creates the lock file for path exclusively, retrying while another
+// process holds it.
+func lock(path string) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			return f.Close()
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("failed to lock state file: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("state file is locked by another process (remove %s if it is stale)", lockPath(path))
+		}
+		time.Sleep(lockInterval)
+	}
+}
+
+// unlock removes the lock file for path.
+func unlock(path string) error {
+	if err := os.Remove(lockPath(path)); err != nil {
+		return fmt.Errorf("failed to unlock state file: %w", err)
+	}
+	return nil
+}
+
+// load reads the salt and digests from path, or prepares a new store with a
+// fresh salt if the file does not exist.
+func load(path string) (*Store, error) {
+	s := &Store{path: path, seen: make(map[string]bool)}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.salt = make([]byte, saltLength)
+		if _, err := rand.Read(s.salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		s.created = true
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, errors.New("state file is empty or unreadable")
+	}
+	saltHex, ok := strings.CutPrefix(scanner.Text(), header)
+	if !ok {
+		return nil, errors.New("state file has an unknown format")
+	}
+	if s.salt, err = hex.DecodeString(saltHex); err != nil || len(s.salt) == 0 {
+		return nil, errors.New("state file has an invalid salt")
+	}
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			s.seen[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	return s, nil
+}
+
+// digest returns the salted hash recorded for password.
+func (s *Store) digest(password string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(password))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Contains reports whether password was recorded in the file or added since Open.
+func (s *Store) Contains(password string) bool {
+	return s.seen[s.digest(password)]
+}
+
+// Add records password. It is written to the file by Close.
+func (s *Store) Add(password string) {
+	d := s.digest(password)
+	if !s.seen[d] {
+		s.seen[d] = true
+		s.pending = append(s.pending, d)
+	}
+}
+
+// Len returns the number of distinct passwords recorded.
+func (s *Store) Len() int {
+	return len(s.seen)
+}
+
+// Close appends the passwords added since Open to the file and releases the lock.
+func (s *Store) Close() error {
+	err := s.flush()
+	if uerr := unlock(s.path); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// Discard releases the lock without writing the passwords added since Open.
+func (s *Store) Discard() error {
+	s.pending, s.created = nil, false
+	return unlock(s.path)
+}
+
+// flush appends the pending digests to the file, writing the header first if
+// the file is new.
+func (s *Store) flush() error {
+	if len(s.pending) == 0 && !s.created {
+		return nil
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	var b strings.Builder
+	if s.created {
+		b.WriteString(header + hex.EncodeToString(s.salt) + "\n")
+	}
+	for _, d := range s.pending {
+		b.WriteString(d + "\n")
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	s.pending, s.created = nil, false
+	return nil
+}
@@ -0,0 +1,92 @@
+package statefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStore_RoundTrip checks that added passwords persist as salted hashes and
+// are found again after reopening.
+func TestStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Add("first-secret")
+	s.Add("second-secret")
+	s.Add("first-secret")
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Fatal("state file contains a plaintext password")
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Errorf("expected a header and 2 digests, got %d lines", len(lines))
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+	if !s.Contains("first-secret") || !s.Contains("second-secret") {
+		t.Error("expected recorded passwords to be found after reopening")
+	}
+	if s.Contains("third-secret") || s.Len() != 2 {
+		t.Errorf("unexpected contents: len %d", s.Len())
+	}
+}
+
+// TestStore_Lock checks that a second Open fails while the lock is held and
+// that Discard releases it without writing.
+func TestStore_Lock(t *testing.T) {
+	orig := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = orig })
+
+	path := filepath.Join(t.TempDir(), "state")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Fatal("expected error while the state file is locked")
+	}
+
+	s.Add("secret")
+	if err := s.Discard(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected Discard not to write the state file")
+	}
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("expected the lock to be released: %v", err)
+	}
+	s.Close()
+}
+
+// TestOpen_Invalid checks that a file in an unknown format is rejected.
+func TestOpen_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(path, []byte("not a state file\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("expected error for an unknown format")
+	}
+	if _, err := os.Stat(lockPath(path)); !os.IsNotExist(err) {
+		t.Error("expected the lock to be released after a failed Open")
+	}
+}